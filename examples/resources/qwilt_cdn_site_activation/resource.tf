@@ -3,4 +3,16 @@ resource "qwilt_cdn_site_activation" "example" {
   site_id     = qwilt_cdn_site_configuration.example.site_id
   revision_id = qwilt_cdn_site_configuration.example.revision_id
   #certificate_id = qwilt_cdn_certificate.example.cert_id
+
+  # Wait until the configuration is deployed to the CDN, not only accepted
+  #wait_for_completion = true
+
+  # Publish the active revision again whenever one of these values changes
+  #republish_triggers = {
+  #  certificate = qwilt_cdn_certificate.example.pk_hash
+  #}
+
+  #timeouts {
+  #  create = "30m"
+  #}
 }
\ No newline at end of file
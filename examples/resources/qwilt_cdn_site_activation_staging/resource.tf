@@ -1,6 +1,6 @@
 
-#Activation cannot be initiated while a previous activation is still 
-#in progress.
+#An activation started while a previous activation is still in progress
+#waits for it to end, within the timeouts, and is then attempted once more.
 
 
 resource "qwilt_cdn_site_activation_staging" "example" {
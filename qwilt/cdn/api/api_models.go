@@ -13,6 +13,9 @@ import (
 )
 
 const SITES_HOSTNAME = "media-sites"
+const CERT_MANAGER_HOSTNAME = "cert-manager"
+const DEVICE_IP_HOSTNAME = "device-ip"
+const LOGIN_HOSTNAME = "login"
 const OPERATION_TYPE_UNPUBLISH = "Unpublish"
 
 // SiteConfiguration - Model for the SiteConfiguration object
@@ -127,6 +130,14 @@ type PubOp struct {
 	ValidatorsErrDetails        json.RawMessage `json:"validatorsErrDetails"`
 }
 
+// ValidatorError - Model for one failure reported in the validatorsErrDetails of a publishing operation
+type ValidatorError struct {
+	Validator string `json:"validator"`
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+	Message   string `json:"message"`
+}
+
 // PubRequest - Model for requesting a new Publish operation
 type PubRequest struct {
 	RevisionId string `json:"revisionId"`
@@ -238,3 +249,12 @@ type DeviceIpsModel struct {
 	CreateTimeMillis int                              `json:"createTimeMillis"`
 	IpData           map[string]NetworkDeviceIpsModel `json:"ipData"`
 }
+
+// ErrorResponse - Model for the error payload returned by the APIs
+type ErrorResponse struct {
+	Status    int             `json:"status"`
+	Error     string          `json:"error"`
+	Message   string          `json:"message"`
+	Path      string          `json:"path"`
+	Timestamp json.RawMessage `json:"timestamp"`
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"strconv"
 )
 
@@ -113,6 +114,10 @@ func (r *certificateResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Description: "The certificate type.",
 				Computed:    true,
 			},
+			"force_overwrite": schema.BoolAttribute{
+				Description: "Update the certificate even if it was modified outside Terraform, for example in the Qwilt portal, since it was planned. Terraform refreshes the certificate before planning, so only the changes made between plan and apply are caught. By default such an update fails.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -137,7 +142,7 @@ func (r *certificateResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	// Create new site
-	certResp, err := r.client.CreateCertificate(certRequest)
+	certResp, err := r.client.CreateCertificate(ctx, certRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating Qwilt CDN Certificate",
@@ -158,6 +163,7 @@ func (r *certificateResource) Create(ctx context.Context, req resource.CreateReq
 		PkHash(certResp.PkHash).
 		Tenant(certResp.Tenant).
 		Domain(certResp.Domain).
+		ForceOverwrite(plan.ForceOverwrite).
 		Build()
 
 	// Set state to fully populated data
@@ -179,8 +185,13 @@ func (r *certificateResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 
 	// Get refreshed certificate value from client
-	certResp, err := r.client.GetCertificate(state.CertId, true)
+	certResp, err := r.client.GetCertificate(ctx, state.CertId.ValueInt64(), true)
 	if err != nil {
+		if cdnclient.IsNotFound(err) {
+			tflog.Warn(ctx, "certificateResource: certificate not found, removing from state", map[string]any{"cert_id": state.CertId.ValueInt64()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading Qwilt CDN Certificate",
 			"Could not read Qwilt CDN Certificate ID "+state.CertId.String()+": "+err.Error(),
@@ -200,6 +211,7 @@ func (r *certificateResource) Read(ctx context.Context, req resource.ReadRequest
 		PkHash(certResp.PkHash).
 		Tenant(certResp.Tenant).
 		Domain(certResp.Domain).
+		ForceOverwrite(state.ForceOverwrite).
 		Build()
 
 	// Set refreshed state
@@ -237,8 +249,27 @@ func (r *certificateResource) Update(ctx context.Context, req resource.UpdateReq
 		//Email:            plan.Email.ValueString(),
 	}
 
-	// Create new site
-	certResp, err := r.client.UpdateCertificate(state.CertId.ValueInt64(), certRequest)
+	var certResp *api.Certificate
+	var err error
+	if plan.ForceOverwrite.ValueBool() {
+		certResp, err = r.client.UpdateCertificate(ctx, state.CertId.ValueInt64(), certRequest)
+	} else {
+		lastRead := api.Certificate{
+			Certificate:      state.Certificate.ValueString(),
+			CertificateChain: state.CertificateChain.ValueString(),
+			Description:      state.Description.ValueString(),
+			PkHash:           state.PkHash.ValueString(),
+		}
+		certResp, err = r.client.UpdateCertificateIfUnmodified(ctx, state.CertId.ValueInt64(), lastRead, certRequest)
+	}
+	if cdnclient.IsModified(err) {
+		resp.Diagnostics.AddError(
+			"Qwilt CDN Certificate Modified Outside Terraform",
+			"Could not update Qwilt CDN Certificate, the "+err.Error()+" since terraform plan. "+
+				"Run terraform plan again to review the changes, or set force_overwrite = true to overwrite them.",
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Qwilt CDN Certificate",
@@ -259,6 +290,7 @@ func (r *certificateResource) Update(ctx context.Context, req resource.UpdateReq
 		PkHash(certResp.PkHash).
 		Tenant(certResp.Tenant).
 		Domain(certResp.Domain).
+		ForceOverwrite(plan.ForceOverwrite).
 		Build()
 
 	// Set state to fully populated data
@@ -280,8 +312,8 @@ func (r *certificateResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 
 	// Delete existing site
-	err := r.client.DeleteCertificate(state.CertId)
-	if err != nil {
+	err := r.client.DeleteCertificate(ctx, state.CertId.ValueInt64())
+	if err != nil && !cdnclient.IsNotFound(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting Qwilt CDN Certificate",
 			"Could not delete Qwilt CDN Certificate, unexpected error: "+err.Error(),
@@ -296,18 +328,18 @@ func (r *certificateResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = cdnclient.NewCertificatesClient(client)
+	r.client = providerData.Facade.CertificatesClient
 }
 
 func (r *certificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -121,15 +121,6 @@ func (d *qwiltCertificatesDataSource) Schema(_ context.Context, _ datasource.Sch
 func (d *qwiltCertificatesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state qwiltCertificatesDataSourceModel
 
-	certs, err := d.client.GetCertificates(true)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Qwilt Certificates",
-			err.Error(),
-		)
-		return
-	}
-
 	// Get state
 	diags := resp.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -151,8 +142,19 @@ func (d *qwiltCertificatesDataSource) Read(ctx context.Context, _ datasource.Rea
 	}
 
 	// Map response body to model
-	// Get cert(s)
-	for _, cert := range certs {
+	// Get cert(s), stopping once the filtered certificate is found
+	certs, err := d.client.ListCertificates(ctx, true)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Qwilt Certificates",
+			err.Error(),
+		)
+		return
+	}
+	defer certs.Close()
+
+	for certs.Next() {
+		cert := certs.Value()
 		if !certIdFilter.IsNull() && cert.CertId != certIdFilter.ValueInt64() {
 			continue
 		}
@@ -170,6 +172,16 @@ func (d *qwiltCertificatesDataSource) Read(ctx context.Context, _ datasource.Rea
 		}
 
 		state.Cert = append(state.Cert, certState)
+		if !certIdFilter.IsNull() {
+			break
+		}
+	}
+	if err := certs.Err(); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Qwilt Certificates",
+			err.Error(),
+		)
+		return
 	}
 
 	// Set state
@@ -185,15 +197,15 @@ func (d *qwiltCertificatesDataSource) Configure(_ context.Context, req datasourc
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = cdnclient.NewCertificatesClient(client)
+	d.client = providerData.Facade.CertificatesClient
 }
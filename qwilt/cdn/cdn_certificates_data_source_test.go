@@ -25,6 +25,7 @@ func TestCertificatesDataResource(t *testing.T) {
 
 	//set this after running script generate_dev_overrides.sh
 	SetDevOverrides()
+	setupTestApi(t)
 
 	tfBinaryPath := "terraform"
 
@@ -13,6 +13,7 @@ import (
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
 	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
 	cdnmodel "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/model"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -25,6 +26,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -50,7 +52,7 @@ func (r *certificateTemplateResource) Metadata(_ context.Context, req resource.M
 }
 
 // Schema defines the schema for the resource.
-func (r *certificateTemplateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *certificateTemplateResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages a Qwilt CDN Certificate Template.<br><br>" +
 			"This resource supports the Certificate Signing Request (CSR) workflow. <br><br>" +
@@ -137,6 +139,9 @@ func (r *certificateTemplateResource) Schema(_ context.Context, _ resource.Schem
 				Computed:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Delete: true}, DEFAULT_CERTIFICATE_TEMPLATE_TIMEOUT),
+		},
 	}
 }
 
@@ -150,6 +155,14 @@ func (r *certificateTemplateResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Create(ctx, DEFAULT_CERTIFICATE_TEMPLATE_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Generate API request body from plan
 	certRequest := api.CertificateTemplateCreateRequest{
 		CommonName:                     plan.CommonName.ValueString(),
@@ -165,7 +178,7 @@ func (r *certificateTemplateResource) Create(ctx context.Context, req resource.C
 	}
 
 	// Create new site
-	certResp, err := r.client.CreateCertificateTemplate(certRequest)
+	certResp, err := r.client.CreateCertificateTemplate(ctx, certRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating Qwilt CDN Certificate Template",
@@ -183,7 +196,7 @@ func (r *certificateTemplateResource) Create(ctx context.Context, req resource.C
 			return
 		}
 		lastCsrId := certResp.CsrIds[len(certResp.CsrIds)-1]
-		domainsList, err := r.client.GetCsrClient().GetChallengeDelegationDomainsListFromCsrId(lastCsrId)
+		domainsList, err := r.client.GetCsrClient().GetChallengeDelegationDomainsListFromCsrId(ctx, lastCsrId)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting Challenge Delegation Domains List",
@@ -211,6 +224,7 @@ func (r *certificateTemplateResource) Create(ctx context.Context, req resource.C
 		LastCertificateID(certResp.LastCertificateID).
 		AddSANs(certResp.SANs...).
 		AddCsrIds(certResp.CsrIds...).
+		Timeouts(plan.Timeouts).
 		Build()
 
 	// Set state to fully populated data
@@ -232,8 +246,13 @@ func (r *certificateTemplateResource) Read(ctx context.Context, req resource.Rea
 	}
 
 	// Get refreshed certificate value from client
-	certResp, err := r.client.GetCertificateTemplate(state.CertificateTemplateId)
+	certResp, err := r.client.GetCertificateTemplate(ctx, state.CertificateTemplateId.ValueInt64())
 	if err != nil {
+		if cdnclient.IsNotFound(err) {
+			tflog.Warn(ctx, "certificateTemplateResource: certificate template not found, removing from state", map[string]any{"certificate_template_id": state.CertificateTemplateId.ValueInt64()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading Qwilt CDN CertificateTemplate",
 			"Could not read Qwilt CDN CertificateTemplate ID "+state.CertificateTemplateId.String()+": "+err.Error(),
@@ -254,6 +273,7 @@ func (r *certificateTemplateResource) Read(ctx context.Context, req resource.Rea
 		LastCertificateID(certResp.LastCertificateID).
 		AddSANs(certResp.SANs...).
 		AddCsrIds(certResp.CsrIds...).
+		Timeouts(state.Timeouts).
 		Build()
 
 	// Set refreshed state
@@ -265,7 +285,25 @@ func (r *certificateTemplateResource) Read(ctx context.Context, req resource.Rea
 }
 
 // Update Updates the resource and sets the updated Terraform state on success.
-func (r *certificateTemplateResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+// All the other attributes require a replacement, so only the timeouts can change.
+func (r *certificateTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan cdnmodel.CertificateTemplate
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state cdnmodel.CertificateTemplate
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete Deletes the resource and removes the Terraform state on success.
@@ -278,9 +316,17 @@ func (r *certificateTemplateResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
+	timeout, diags := state.Timeouts.Delete(ctx, DEFAULT_CERTIFICATE_TEMPLATE_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Delete existing site
-	err := r.client.DeleteCertificateTemplate(state.CertificateTemplateId)
-	if err != nil {
+	err := r.client.DeleteCertificateTemplate(ctx, state.CertificateTemplateId.ValueInt64())
+	if err != nil && !cdnclient.IsNotFound(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting Qwilt CDN CertificateTemplate",
 			"Could not delete Qwilt CDN CertificateTemplate, unexpected error: "+err.Error(),
@@ -295,18 +341,18 @@ func (r *certificateTemplateResource) Configure(_ context.Context, req resource.
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = cdnclient.NewCertificateTemplateClient(client)
+	r.client = providerData.Facade.CertificateTemplateClient
 }
 
 func (r *certificateTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -24,6 +24,7 @@ func TestCertificateTemplateResource(t *testing.T) {
 
 	//set this after running script generate_dev_overrides.sh
 	SetDevOverrides()
+	setupTestApi(t)
 
 	tfBinaryPath := "terraform"
 
@@ -145,15 +145,6 @@ func (d *qwiltCertificateTemplatesDataSource) Schema(_ context.Context, _ dataso
 func (d *qwiltCertificateTemplatesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state qwiltCertificateTemplatesDataSourceModel
 
-	certs, err := d.client.GetCertificateTemplates()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Qwilt Certificate Templates",
-			err.Error(),
-		)
-		return
-	}
-
 	// Get state
 	diags := resp.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -175,8 +166,19 @@ func (d *qwiltCertificateTemplatesDataSource) Read(ctx context.Context, _ dataso
 	}
 
 	// Map response body to model
-	// Get cert(s)
-	for _, cert := range certs {
+	// Get cert(s), stopping once the filtered template is found
+	certs, err := d.client.ListCertificateTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Qwilt Certificate Templates",
+			err.Error(),
+		)
+		return
+	}
+	defer certs.Close()
+
+	for certs.Next() {
+		cert := certs.Value()
 		if !idFilter.IsNull() && cert.CertificateTemplateID != idFilter.ValueInt64() {
 			continue
 		}
@@ -205,6 +207,16 @@ func (d *qwiltCertificateTemplatesDataSource) Read(ctx context.Context, _ dataso
 		}
 
 		state.CertificateTemplates = append(state.CertificateTemplates, certState)
+		if !idFilter.IsNull() {
+			break
+		}
+	}
+	if err := certs.Err(); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Qwilt Certificate Templates",
+			err.Error(),
+		)
+		return
 	}
 
 	// Set state
@@ -220,15 +232,15 @@ func (d *qwiltCertificateTemplatesDataSource) Configure(_ context.Context, req d
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = cdnclient.NewCertificateTemplateClient(client)
+	d.client = providerData.Facade.CertificateTemplateClient
 }
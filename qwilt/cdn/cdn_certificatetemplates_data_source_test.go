@@ -26,6 +26,7 @@ func TestCertificateTemplatesDataResource(t *testing.T) {
 
 	//set this after running script generate_dev_overrides.sh
 	SetDevOverrides()
+	setupTestApi(t)
 
 	tfBinaryPath := "terraform"
 
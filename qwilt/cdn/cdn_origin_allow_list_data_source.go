@@ -88,7 +88,7 @@ func (d *qwiltOriginAllowListDataSource) Schema(_ context.Context, _ datasource.
 func (d *qwiltOriginAllowListDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state qwiltOriginAllowListDataSourceModel
 
-	deviceIpsResp, err := d.client.GetOriginAllowList()
+	deviceIpsResp, err := d.client.GetOriginAllowList(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read Qwilt IpData",
@@ -135,15 +135,15 @@ func (d *qwiltOriginAllowListDataSource) Configure(_ context.Context, req dataso
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = cdnclient.NewDeviceIpsClient(client)
+	d.client = providerData.Facade.DeviceIpsClient
 }
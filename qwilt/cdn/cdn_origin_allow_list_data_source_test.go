@@ -23,6 +23,7 @@ func TestOriginAllowListDataResource(t *testing.T) {
 
 	//set this after running script generate_dev_overrides.sh
 	SetDevOverrides()
+	setupTestApi(t)
 
 	tfBinaryPath := "terraform"
 
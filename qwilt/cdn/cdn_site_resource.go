@@ -79,6 +79,10 @@ func (r *siteResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Description: "When the site last updated, in epoch time.",
 				Computed:    true,
 			},
+			"force_overwrite": schema.BoolAttribute{
+				Description: "Update the site even if it was modified outside Terraform, for example in the Qwilt portal, since it was planned. Terraform refreshes the site before planning, so only the changes made between plan and apply are caught. By default such an update fails.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -101,7 +105,7 @@ func (r *siteResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 
 	// Create new site
-	siteResp, err := r.client.CreateSite(siteCreate)
+	siteResp, err := r.client.CreateSite(ctx, siteCreate)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating Qwilt CDN Site",
@@ -118,6 +122,7 @@ func (r *siteResource) Create(ctx context.Context, req resource.CreateRequest, r
 		RoutingMethod(siteResp.RoutingMethod).
 		SiteDnsCnameDelegationTarget(siteResp.SiteDnsCnameDelegationTarget).
 		LastUpdateTimeMilli(siteResp.LastUpdateTimeMilli).
+		ForceOverwrite(plan.ForceOverwrite).
 		Build()
 
 	// Set state to fully populated data
@@ -141,8 +146,13 @@ func (r *siteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	tflog.Info(ctx, "siteResource: read\n")
 
 	// Get refreshed site value from QC
-	siteResp, err := r.client.GetSite(state.SiteId.ValueString(), "", false, false)
+	siteResp, err := r.client.GetSite(ctx, state.SiteId.ValueString(), "", false, false)
 	if err != nil {
+		if cdnclient.IsNotFound(err) {
+			tflog.Warn(ctx, "siteResource: site not found, removing from state", map[string]any{"site_id": state.SiteId.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading Qwilt CDN Site",
 			"Could not read Qwilt CDN Site ID "+state.SiteId.ValueString()+": "+err.Error(),
@@ -158,6 +168,7 @@ func (r *siteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		RoutingMethod(siteResp.RoutingMethod).
 		SiteDnsCnameDelegationTarget(siteResp.SiteDnsCnameDelegationTarget).
 		LastUpdateTimeMilli(siteResp.LastUpdateTimeMilli).
+		ForceOverwrite(state.ForceOverwrite).
 		Build()
 
 	// Set refreshed state
@@ -193,7 +204,21 @@ func (r *siteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		SiteName: plan.SiteName.ValueString(),
 	}
 
-	siteResp, err := r.client.UpdateSite(plan.SiteId.ValueString(), siteUpdate)
+	var siteResp *api.Site
+	var err error
+	if plan.ForceOverwrite.ValueBool() || state.LastUpdateTimeMilli.IsNull() {
+		siteResp, err = r.client.UpdateSite(ctx, plan.SiteId.ValueString(), siteUpdate)
+	} else {
+		siteResp, err = r.client.UpdateSiteIfUnmodified(ctx, plan.SiteId.ValueString(), state.LastUpdateTimeMilli.ValueInt64(), siteUpdate)
+	}
+	if cdnclient.IsModified(err) {
+		resp.Diagnostics.AddError(
+			"Qwilt CDN Site Modified Outside Terraform",
+			"Could not update Qwilt CDN Site, the "+err.Error()+" since terraform plan. "+
+				"Run terraform plan again to review the changes, or set force_overwrite = true to overwrite them.",
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Qwilt CDN Site",
@@ -210,6 +235,7 @@ func (r *siteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		RoutingMethod(state.RoutingMethod.ValueString()). //use the routing-method from the state in case it had an override in QC
 		SiteDnsCnameDelegationTarget(siteResp.SiteDnsCnameDelegationTarget).
 		LastUpdateTimeMilli(siteResp.LastUpdateTimeMilli).
+		ForceOverwrite(plan.ForceOverwrite).
 		Build()
 
 	// Set state to fully populated data
@@ -233,8 +259,8 @@ func (r *siteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	tflog.Debug(ctx, "siteResource: delete\n")
 
 	// Delete the site permanently
-	err := r.client.DeleteSite(state.SiteId.ValueString())
-	if err != nil {
+	err := r.client.DeleteSite(ctx, state.SiteId.ValueString())
+	if err != nil && !cdnclient.IsNotFound(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting Qwilt CDN Site",
 			"Could not delete Qwilt CDN Site, unexpected error: "+err.Error(),
@@ -249,18 +275,18 @@ func (r *siteResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source ure Type",
-			fmt.Sprintf("Expected *qwiltcdnapi.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = cdnclient.NewSiteClient(api.SITES_HOSTNAME, client)
+	r.client = providerData.Facade.SiteClient
 }
 
 func (r *siteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -9,14 +9,19 @@ package cdn
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
 	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
 	cdnmodel "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/model"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/validators"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -24,13 +29,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource                = &siteActivationResource{}
-	_ resource.ResourceWithConfigure   = &siteActivationResource{}
-	_ resource.ResourceWithImportState = &siteActivationResource{}
+	_ resource.Resource                 = &siteActivationResource{}
+	_ resource.ResourceWithConfigure    = &siteActivationResource{}
+	_ resource.ResourceWithImportState  = &siteActivationResource{}
+	_ resource.ResourceWithUpgradeState = &siteActivationResource{}
 )
 
 // NewSiteActivationResource is a helper function to simplify the provider implementation.
@@ -43,8 +50,9 @@ func NewSiteActivationResource() resource.Resource {
 
 // siteActivationResource is the resource implementation.
 type siteActivationResource struct {
-	client *cdnclient.SiteClientFacade
-	target string
+	client    *cdnclient.SiteClientFacade
+	siteLocks *SiteLocks
+	target    string
 }
 
 // Metadata returns the resource type name.
@@ -53,12 +61,14 @@ func (r *siteActivationResource) Metadata(_ context.Context, req resource.Metada
 }
 
 // Schema defines the schema for the resource.
-func (r *siteActivationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *siteActivationResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		// Version 1 decodes validators_err_details, see UpgradeState
+		Version: 1,
 		MarkdownDescription: "Manages a Qwilt CDN site activation and certificate assignment.<br><br>" +
 			"Notes:<br>" +
 			" - This resource takes a long time to fully apply.<br>" +
-			" - If a site activation attempt fails, it may be due to another publish operation already in progress for the same site_id.<br>" +
+			" - The publishing operations of a site_id run one at a time within a Terraform run. If a publish operation started outside of Terraform for the same site_id is in progress, the activation waits for it to end, within the timeouts, and is then attempted once more.<br>" +
 			" - Run ```terraform refresh``` to sync the state of this resource explicitly.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -140,20 +150,56 @@ func (r *siteActivationResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Description: "The operation type (Publish, Unpublish) that was initiated with the request. An Unpublish operation removes a delivery service from the CDN.",
 				Computed:    true,
 			},
-			//"status_line": schema.ListAttribute{
-			//	ElementType: types.StringType,
-			//	Description: "Additional information related to the publish status.",
-			//	Computed:    true,
-			//},
+			"status_line": schema.ListAttribute{
+				ElementType: types.StringType,
+				Description: "Additional information related to the publish status.",
+				Computed:    true,
+			},
 			"is_active": schema.BoolAttribute{
 				Description: "Indicates if the configuration is active or inactive.",
 				Computed:    true,
 			},
-			"validators_err_details": schema.StringAttribute{
-				Description: "Details about errors generated during validation.",
+			"validators_err_details": schema.ListNestedAttribute{
+				Description: "The failures reported by the validation of the publishing operation, one per failure. Empty when the validation passed.",
 				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"validator": schema.StringAttribute{
+							Description: "The validator that reported the failure.",
+							Computed:    true,
+						},
+						"host": schema.StringAttribute{
+							Description: "The host of the site configuration the failure relates to.",
+							Computed:    true,
+						},
+						"path": schema.StringAttribute{
+							Description: "The path of the failing metadata within the host_index of the site configuration.",
+							Computed:    true,
+						},
+						"message": schema.StringAttribute{
+							Description: "The description of the failure.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"cancel_on_timeout": schema.BoolAttribute{
+				Description: "Cancel the publishing operation when the provider gives up waiting for it, on a timeout or an interruption, so it does not block the next publishing operation of the site. Defaults to true.",
+				Optional:    true,
+			},
+			"republish_triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Description: "Arbitrary values that, when changed, publish the active revision of the site again through the Republish API, for example after a certificate renewal. Changing them together with revision_id publishes the new revision instead.",
+				Optional:    true,
+			},
+			"wait_for_completion": schema.BoolAttribute{
+				Description: "Wait until the publishing operation is deployed to the CDN, with a 'Success' publish status, rather than only accepted. Fails when the operation ends as 'Failed' or 'Aborted'. Defaults to false.",
+				Optional:    true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx, timeouts.Opts{Create: true, Update: true, Delete: true}, DEFAULT_PUBLISH_TIMEOUT),
+		},
 	}
 }
 
@@ -169,6 +215,16 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 
 	tflog.Info(ctx, "siteActivationResource: create")
 
+	timeout, diags := plan.Timeouts.Create(ctx, DEFAULT_PUBLISH_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// One deadline for the whole operation, each step gets the time left
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
 	// Evaluate the certificate ID
 	var certificateId int64
 	switch {
@@ -177,7 +233,7 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 		certificateId = plan.CertificateId.ValueInt64()
 	// If the certificate ID is not set and certificate template ID is set, get the certificate ID
 	case !plan.CertificateTemplateId.IsNull():
-		certificateTemplate, err := r.client.GetCertificateTemplate(plan.CertificateTemplateId)
+		certificateTemplate, err := r.client.GetCertificateTemplate(ctx, plan.CertificateTemplateId.ValueInt64())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting Certificate Template",
@@ -190,7 +246,7 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 		// Inform the user and return an error.
 		if certificateTemplate.LastCertificateID == nil {
 			if certificateTemplate.AutoManagedCertificateTemplate {
-				domainsList, err := r.client.GetChallengeDelegationDomainsListFromCertificateTemplateId(plan.CertificateTemplateId)
+				domainsList, err := r.client.GetChallengeDelegationDomainsListFromCertificateTemplateId(ctx, plan.CertificateTemplateId.ValueInt64())
 				if err != nil {
 					resp.Diagnostics.AddError(
 						"Error Getting Challenge Delegation Domains List",
@@ -213,9 +269,15 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 		certificateId = *certificateTemplate.LastCertificateID
 	}
 
+	unlock := r.lockSite(ctx, plan.SiteId.ValueString(), &resp.Diagnostics)
+	if unlock == nil {
+		return
+	}
+	defer unlock()
+
 	// If we have an https site to publish, link the certificate to the site
 	if certificateId != 0 {
-		_, err := r.client.LinkSiteCertificate(plan.SiteId.ValueString(), strconv.Itoa(int(certificateId)))
+		_, err := r.client.LinkSiteCertificate(ctx, plan.SiteId.ValueString(), strconv.Itoa(int(certificateId)))
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Linking Certificate to Qwilt CDN Site",
@@ -226,7 +288,9 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 	}
 
 	// Publish the site
-	pubOpResp, err := r.client.Publish(plan.SiteId.ValueString(), plan.RevisionId.ValueString(), r.target)
+	pubOpResp, err := r.startPubOp(ctx, plan.SiteId.ValueString(), r.target, deadline, &resp.Diagnostics, func() (*api.PubOp, error) {
+		return r.client.Publish(ctx, plan.SiteId.ValueString(), plan.RevisionId.ValueString(), r.target)
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Publishing Qwilt CDN Site",
@@ -235,27 +299,8 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	timeout := cdnclient.ACCEPTANCE_TIMEOUT
-	pubOpResp, err = r.client.GetAndWaitForPubOpAcceptance(plan.SiteId.ValueString(), pubOpResp.PublishId, timeout) // Function that checks status of 'x' from backend
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Timeout while Waiting for validation status in Qwilt CDN Site Publish operation",
-			"Could not get Qwilt CDN Site Publish acceptance status. err: "+err.Error(),
-		)
-		return
-	}
-
-	tflog.Info(ctx, "siteActivationResource: PUBLISH ACCEPTANCE STATUS after timeout IS: "+pubOpResp.PublishAcceptanceStatus+"\n")
-	if pubOpResp.PublishAcceptanceStatus == cdnclient.ACCEPTANCE_STATUS_INVALID ||
-		pubOpResp.PublishAcceptanceStatus == cdnclient.ACCEPTANCE_STATUS_DISMISSED {
-		details := fmt.Sprintf("Publish failed for Qwilt CDN Site %s\n. Acceptance Status: %s\n. Err: %s\n. Status line: %s\b",
-			plan.SiteId,
-			pubOpResp.PublishAcceptanceStatus,
-			pubOpResp.ValidatorsErrDetails,
-			strings.Join(pubOpResp.StatusLine, ","))
-		resp.Diagnostics.AddError(
-			"Error during PUBLISH for Qwilt CDN Site", details)
-
+	pubOpResp = r.waitForPubOp(ctx, plan, pubOpResp.PublishId, deadline, &resp.Diagnostics)
+	if pubOpResp == nil {
 		return
 	}
 
@@ -277,9 +322,13 @@ func (r *siteActivationResource) Create(ctx context.Context, req resource.Create
 		PublishStatus(pubOpResp.PublishStatus).
 		AcceptanceStatus(pubOpResp.PublishAcceptanceStatus).
 		OperationType(pubOpResp.OperationType).
-		ValidateErrDetails(pubOpResp.ValidatorsErrDetails).
+		ValidateErrDetails(cdnclient.ParseValidatorErrors(pubOpResp.ValidatorsErrDetails)).
 		IsActive(pubOpResp.IsActive).
-		//StatusLine(pubOpResp.StatusLine).
+		StatusLine(pubOpResp.StatusLine).
+		WaitForCompletion(plan.WaitForCompletion).
+		CancelOnTimeout(plan.CancelOnTimeout).
+		RepublishTriggers(plan.RepublishTriggers).
+		Timeouts(plan.Timeouts).
 		Build()
 
 	// Set state to fully populated data
@@ -303,16 +352,26 @@ func (r *siteActivationResource) Read(ctx context.Context, req resource.ReadRequ
 	tflog.Info(ctx, "siteActivationResource: read")
 
 	// Get refreshed site value from CDN
-	pubOpResp, err := r.client.GetPubOp(state.SiteId.ValueString(), state.PublishId.ValueString())
+	pubOpResp, err := r.client.GetPubOp(ctx, state.SiteId.ValueString(), state.PublishId.ValueString())
 	if err != nil {
+		if cdnclient.IsNotFound(err) {
+			tflog.Warn(ctx, "siteActivationResource: publishing operation not found, removing from state", map[string]any{"site_id": state.SiteId.ValueString(), "publish_id": state.PublishId.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading Qwilt CDN Site Publish",
 			"Could not read Qwilt CDN Site Publish "+state.SiteId.ValueString()+": "+err.Error(),
 		)
 		return
 	}
-	certsResp, err := r.client.GetSiteCertificates(state.SiteId.ValueString(), "")
+	certsResp, err := r.client.GetSiteCertificates(ctx, state.SiteId.ValueString(), "")
 	if err != nil {
+		if cdnclient.IsNotFound(err) {
+			tflog.Warn(ctx, "siteActivationResource: site not found, removing from state", map[string]any{"site_id": state.SiteId.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Getting Certificates for Qwilt CDN Site",
 			"Could not get certificates for Qwilt CDN Site, unexpected error: "+err.Error(),
@@ -332,7 +391,7 @@ func (r *siteActivationResource) Read(ctx context.Context, req resource.ReadRequ
 			return
 		}
 
-		certResp, err := r.client.GetCertificate(types.Int64Value(certId), false)
+		certResp, err := r.client.GetCertificate(ctx, certId, false)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting Certificate for Qwilt CDN Site",
@@ -348,7 +407,7 @@ func (r *siteActivationResource) Read(ctx context.Context, req resource.ReadRequ
 					"Could not convert certificate ID for Qwilt CDN Site, unexpected error: "+err.Error(),
 				)
 			}
-			csrResp, err := r.client.GetCertificateSigningRequest(types.Int64Value(int64(csrId)))
+			csrResp, err := r.client.GetCertificateSigningRequest(ctx, int64(csrId))
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error Getting Certificate Signing Request for Qwilt CDN Site",
@@ -391,8 +450,12 @@ func (r *siteActivationResource) Read(ctx context.Context, req resource.ReadRequ
 		AcceptanceStatus(pubOpResp.PublishAcceptanceStatus).
 		OperationType(pubOpResp.OperationType).
 		IsActive(pubOpResp.IsActive).
-		ValidateErrDetails(pubOpResp.ValidatorsErrDetails).
-		//StatusLine(pubOpResp.StatusLine).
+		ValidateErrDetails(cdnclient.ParseValidatorErrors(pubOpResp.ValidatorsErrDetails)).
+		StatusLine(pubOpResp.StatusLine).
+		WaitForCompletion(state.WaitForCompletion).
+		CancelOnTimeout(state.CancelOnTimeout).
+		RepublishTriggers(state.RepublishTriggers).
+		Timeouts(state.Timeouts).
 		Build()
 
 	// Set refreshed state
@@ -423,12 +486,38 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	// Changing the republish_triggers only pushes the active revision again, while
+	// changing the timeouts, wait_for_completion or cancel_on_timeout only does not publish again
+	republish := plan.RevisionId.Equal(state.RevisionId) &&
+		plan.CertificateId.Equal(state.CertificateId) &&
+		plan.CertificateTemplateId.Equal(state.CertificateTemplateId)
+	if republish && plan.RepublishTriggers.Equal(state.RepublishTriggers) {
+		state.WaitForCompletion = plan.WaitForCompletion
+		state.CancelOnTimeout = plan.CancelOnTimeout
+		state.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+	if republish && !r.isActiveRevision(ctx, plan, &resp.Diagnostics) {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, DEFAULT_PUBLISH_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// One deadline for the whole operation, each step gets the time left
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
 	var lastCertificateId int64
 	switch {
 	case !state.CertificateId.IsNull():
 		lastCertificateId = state.CertificateId.ValueInt64()
 	case !state.CertificateTemplateId.IsNull():
-		certificateTemplate, err := r.client.GetCertificateTemplate(state.CertificateTemplateId)
+		certificateTemplate, err := r.client.GetCertificateTemplate(ctx, state.CertificateTemplateId.ValueInt64())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting Certificate Template",
@@ -445,7 +534,7 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 	case !plan.CertificateId.IsNull():
 		newCertificateId = plan.CertificateId.ValueInt64()
 	case !plan.CertificateTemplateId.IsNull():
-		certificateTemplate, err := r.client.GetCertificateTemplate(plan.CertificateTemplateId)
+		certificateTemplate, err := r.client.GetCertificateTemplate(ctx, plan.CertificateTemplateId.ValueInt64())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting Certificate Template",
@@ -456,10 +545,16 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 		newCertificateId = *certificateTemplate.LastCertificateID
 	}
 
+	unlock := r.lockSite(ctx, plan.SiteId.ValueString(), &resp.Diagnostics)
+	if unlock == nil {
+		return
+	}
+	defer unlock()
+
 	if lastCertificateId != newCertificateId {
 		if lastCertificateId != 0 {
 			//unlink previous certificate
-			err := r.client.UnLinkSiteCertificate(state.SiteId.ValueString(), strconv.Itoa(int(lastCertificateId)))
+			err := r.client.UnLinkSiteCertificate(ctx, state.SiteId.ValueString(), strconv.Itoa(int(lastCertificateId)))
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error UnLinking Certificate to Qwilt CDN Site",
@@ -470,7 +565,7 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 		}
 		if newCertificateId != 0 {
 			//there is a certificate that should be linked
-			_, err := r.client.LinkSiteCertificate(plan.SiteId.ValueString(), strconv.Itoa(int(newCertificateId)))
+			_, err := r.client.LinkSiteCertificate(ctx, plan.SiteId.ValueString(), strconv.Itoa(int(newCertificateId)))
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error Linking Certificate to Qwilt CDN Site",
@@ -481,8 +576,16 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 		}
 	}
 
-	// Publish the site
-	pubOpResp, err := r.client.Publish(plan.SiteId.ValueString(), plan.RevisionId.ValueString(), r.target)
+	// Publish the site, or push its active revision again when only the republish triggers changed
+	if republish {
+		tflog.Info(ctx, "siteActivationResource: REPUBLISH of revision-id: "+plan.RevisionId.ValueString())
+	}
+	pubOpResp, err := r.startPubOp(ctx, plan.SiteId.ValueString(), r.target, deadline, &resp.Diagnostics, func() (*api.PubOp, error) {
+		if republish {
+			return r.client.Republish(ctx, plan.SiteId.ValueString(), r.target)
+		}
+		return r.client.Publish(ctx, plan.SiteId.ValueString(), plan.RevisionId.ValueString(), r.target)
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Publishing Qwilt CDN Site",
@@ -491,27 +594,10 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	timeout := cdnclient.ACCEPTANCE_TIMEOUT
-	pubOpResp, err = r.client.GetAndWaitForPubOpAcceptance(plan.SiteId.ValueString(), pubOpResp.PublishId, timeout) // Function that checks status of 'x' from backend
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Timeout while Waiting for validation status in Qwilt CDN Site Publish operation",
-			"Could not get Qwilt CDN Site Publish acceptance status. err: "+err.Error(),
-		)
-		return
-	}
-	if pubOpResp.PublishAcceptanceStatus == cdnclient.ACCEPTANCE_STATUS_INVALID ||
-		pubOpResp.PublishAcceptanceStatus == cdnclient.ACCEPTANCE_STATUS_DISMISSED {
-		details := fmt.Sprintf("Publish failed for Qwilt CDN Site %s\n. Acceptance Status: %s\n. Err: %s\n. Status line: %s\b",
-			plan.SiteId,
-			pubOpResp.PublishAcceptanceStatus,
-			pubOpResp.ValidatorsErrDetails,
-			strings.Join(pubOpResp.StatusLine, ","))
-		resp.Diagnostics.AddError(
-			"Error during PUBLISH for Qwilt CDN Site", details)
+	pubOpResp = r.waitForPubOp(ctx, plan, pubOpResp.PublishId, deadline, &resp.Diagnostics)
+	if pubOpResp == nil {
 		return
 	}
-	tflog.Info(ctx, "siteActivationResource: PUBLISH ACCEPTANCE STATUS after timeout IS: "+pubOpResp.PublishAcceptanceStatus+"\n")
 
 	// Map response body to schema and populate Computed attribute values
 	newPlan := cdnmodel.NewSiteActivationBuilder().
@@ -531,8 +617,12 @@ func (r *siteActivationResource) Update(ctx context.Context, req resource.Update
 		OperationType(pubOpResp.OperationType).
 		Target(pubOpResp.Target).
 		IsActive(pubOpResp.IsActive).
-		ValidateErrDetails(pubOpResp.ValidatorsErrDetails).
-		//StatusLine(pubOpResp.StatusLine).
+		ValidateErrDetails(cdnclient.ParseValidatorErrors(pubOpResp.ValidatorsErrDetails)).
+		StatusLine(pubOpResp.StatusLine).
+		WaitForCompletion(plan.WaitForCompletion).
+		CancelOnTimeout(plan.CancelOnTimeout).
+		RepublishTriggers(plan.RepublishTriggers).
+		Timeouts(plan.Timeouts).
 		Build()
 
 	// Set state to fully populated data
@@ -555,10 +645,28 @@ func (r *siteActivationResource) Delete(ctx context.Context, req resource.Delete
 
 	tflog.Info(ctx, "siteActivationResource: delete, publish status: "+state.PublishStatus.ValueString())
 
+	timeout, diags := state.Timeouts.Delete(ctx, DEFAULT_PUBLISH_TIMEOUT)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// One deadline for the whole operation, each step gets the time left
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
 	//Deletion semantic is 'unpublish'
 	tflog.Info(ctx, "siteActivationResource: UN-PUBLISH for publish-id: "+state.PublishId.ValueString())
 
-	_, err := r.client.Unpublish(state.SiteId.ValueString(), state.Target.ValueString())
+	unlock := r.lockSite(ctx, state.SiteId.ValueString(), &resp.Diagnostics)
+	if unlock == nil {
+		return
+	}
+	defer unlock()
+
+	_, err := r.startPubOp(ctx, state.SiteId.ValueString(), state.Target.ValueString(), deadline, &resp.Diagnostics, func() (*api.PubOp, error) {
+		return r.client.Unpublish(ctx, state.SiteId.ValueString(), state.Target.ValueString())
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error UnPublishing Qwilt CDN Site",
@@ -569,7 +677,7 @@ func (r *siteActivationResource) Delete(ctx context.Context, req resource.Delete
 
 	if state.CertificateId.ValueInt64() != 0 {
 		//unlink previous certificate
-		err := r.client.UnLinkSiteCertificate(state.SiteId.ValueString(), strconv.Itoa(int(state.CertificateId.ValueInt64())))
+		err := r.client.UnLinkSiteCertificate(ctx, state.SiteId.ValueString(), strconv.Itoa(int(state.CertificateId.ValueInt64())))
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error UnLinking Certificate to Qwilt CDN Site",
@@ -586,18 +694,287 @@ func (r *siteActivationResource) Configure(_ context.Context, req resource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Facade
+	r.siteLocks = providerData.SiteLocks
+}
+
+// lockSite - Waits until the other publishing operations of the site in this run are done, see SiteLocks.
+// Returns the function releasing the lock, or nil with an error diagnostic.
+func (r *siteActivationResource) lockSite(ctx context.Context, siteId string, diags *diag.Diagnostics) func() {
+	tflog.Debug(ctx, "siteActivationResource: waiting for the other publishing operations of site "+siteId)
+	unlock, err := r.siteLocks.Lock(ctx, siteId)
+	if err != nil {
+		diags.AddError(
+			"Error Waiting for Qwilt CDN Site Publishing Operation",
+			"Could not start a publishing operation on Qwilt CDN Site "+siteId+" while another one is in progress: "+err.Error(),
+		)
+		return nil
+	}
+	return unlock
+}
+
+// startPubOp - Starts a publishing operation of the site with start. When the API turns it down with a conflict and
+// another publishing operation of the site is in progress for the target, e.g. one started outside of Terraform,
+// waits for that one to end until deadline and calls start once more. What was waited on is reported as a warning.
+// Any other failure is returned as is: the publishing requests are not idempotent, so they are never sent again
+// unless the API answered that nothing was started.
+func (r *siteActivationResource) startPubOp(ctx context.Context, siteId string, target string, deadline time.Time, diags *diag.Diagnostics, start func() (*api.PubOp, error)) (*api.PubOp, error) {
+	pubOpResp, err := start()
+	if err == nil || !cdnclient.IsConflict(err) {
+		return pubOpResp, err
+	}
+
+	pubOps, listErr := r.client.GetPubOps(ctx, siteId, false, "")
+	if listErr != nil {
+		tflog.Warn(ctx, "siteActivationResource: could not list the publishing operations of site "+siteId+": "+listErr.Error())
+		return nil, err
+	}
+	var blocking *api.PubOp
+	for i := range pubOps {
+		if pubOps[i].PublishStatus == cdnclient.PUBLISH_STATUS_IN_PROGRESS && (target == "" || pubOps[i].Target == target) {
+			blocking = &pubOps[i]
+			break
+		}
+	}
+	if blocking == nil {
+		return nil, err
+	}
+
+	tflog.Info(ctx, "siteActivationResource: waiting for the publishing operation in progress on the site", map[string]any{
+		"site_id":        siteId,
+		"publish_id":     blocking.PublishId,
+		"operation_type": blocking.OperationType,
+		"username":       blocking.Username,
+	})
+	blockingResp, waitErr := r.client.GetAndWaitForPubOpCompletion(ctx, siteId, blocking.PublishId, time.Until(deadline))
+	if waitErr != nil {
+		return nil, fmt.Errorf("%w. Gave up waiting for publishing operation %s (%s started by %s) to end: %s",
+			err, blocking.PublishId, blocking.OperationType, blocking.Username, waitErr.Error())
+	}
+
+	diags.AddWarning(
+		"Waited for another Qwilt CDN Site Publish operation",
+		fmt.Sprintf("Publishing operation %s of Qwilt CDN Site %s (%s of revision %s, started by %s) was in progress. "+
+			"It ended with status %s, then the operation was started again.",
+			blocking.PublishId, siteId, blocking.OperationType, blocking.RevisionId, blocking.Username, blockingResp.PublishStatus),
+	)
+	return start()
+}
+
+// waitForPubOp - Waits until the publishing operation is accepted, and deployed when wait_for_completion is set, up to deadline.
+// Returns the last details of the operation, or nil with an error diagnostic.
+func (r *siteActivationResource) waitForPubOp(ctx context.Context, plan cdnmodel.SiteActivation, publishId string, deadline time.Time, diags *diag.Diagnostics) *api.PubOp {
+	pubOpResp, err := r.client.GetAndWaitForPubOpAcceptance(ctx, plan.SiteId.ValueString(), publishId, time.Until(deadline)) // Function that checks status of 'x' from backend
+	if err != nil {
+		diags.AddError(
+			"Timeout while Waiting for validation status in Qwilt CDN Site Publish operation",
+			"Could not get Qwilt CDN Site Publish acceptance status. err: "+err.Error(),
 		)
+		r.cancelPubOp(ctx, plan, publishId, err, diags)
+		return nil
+	}
 
+	tflog.Info(ctx, "siteActivationResource: PUBLISH ACCEPTANCE STATUS after timeout IS: "+pubOpResp.PublishAcceptanceStatus+"\n")
+	if pubOpResp.PublishAcceptanceStatus == cdnclient.ACCEPTANCE_STATUS_INVALID ||
+		pubOpResp.PublishAcceptanceStatus == cdnclient.ACCEPTANCE_STATUS_DISMISSED {
+		addValidationDiagnostics(plan, pubOpResp, diags)
+		return nil
+	}
+
+	if !plan.WaitForCompletion.ValueBool() {
+		return pubOpResp
+	}
+
+	tflog.Info(ctx, "siteActivationResource: waiting for the deployment of publish-id: "+publishId)
+	pubOpResp, err = r.client.GetAndWaitForPubOpCompletion(ctx, plan.SiteId.ValueString(), publishId, time.Until(deadline))
+	if err != nil {
+		diags.AddError(
+			"Timeout while Waiting for completion of Qwilt CDN Site Publish operation",
+			"Could not get Qwilt CDN Site Publish status. err: "+err.Error(),
+		)
+		r.cancelPubOp(ctx, plan, publishId, err, diags)
+		return nil
+	}
+	if pubOpResp.PublishStatus != cdnclient.PUBLISH_STATUS_SUCCESS {
+		details := fmt.Sprintf("Publish failed for Qwilt CDN Site %s\n. Publish Status: %s\n. Status line: %s\n",
+			plan.SiteId,
+			pubOpResp.PublishStatus,
+			strings.Join(pubOpResp.StatusLine, ","))
+		diags.AddError(
+			"Error during PUBLISH for Qwilt CDN Site", details)
+		return nil
+	}
+	return pubOpResp
+}
+
+// addValidationDiagnostics - Reports a publishing operation that did not pass validation, with one error per failure
+// naming the host and the metadata path within host_index, or a single error with the raw details when there is no
+// failure to name. The errors point at revision_id, which references the qwilt_cdn_site_configuration that produced the revision.
+func addValidationDiagnostics(plan cdnmodel.SiteActivation, pubOp *api.PubOp, diags *diag.Diagnostics) {
+	summary := fmt.Sprintf("Publish failed for Qwilt CDN Site %s, revision %s. Acceptance Status: %s.",
+		plan.SiteId.ValueString(),
+		plan.RevisionId.ValueString(),
+		pubOp.PublishAcceptanceStatus)
+	if len(pubOp.StatusLine) > 0 {
+		summary += " Status line: " + strings.Join(pubOp.StatusLine, ", ") + "."
+	}
+
+	validatorErrors := cdnclient.ParseValidatorErrors(pubOp.ValidatorsErrDetails)
+	if len(validatorErrors) == 0 {
+		details := summary
+		if raw := strings.TrimSpace(string(pubOp.ValidatorsErrDetails)); raw != "" && raw != "null" {
+			details = "Validation details: " + raw + "\n\n" + summary
+		}
+		diags.AddAttributeError(path.Root("revision_id"), "Error during PUBLISH for Qwilt CDN Site", details)
 		return
 	}
 
-	r.client = cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, client)
+	for _, validatorError := range validatorErrors {
+		var location []string
+		if validatorError.Host != "" {
+			location = append(location, "host "+validatorError.Host)
+		}
+		if validatorError.Path != "" {
+			location = append(location, "host_index path "+validatorError.Path)
+		}
+		if validatorError.Validator != "" {
+			location = append(location, "validator "+validatorError.Validator)
+		}
+
+		details := validatorError.Message
+		if len(location) > 0 {
+			details = "In " + strings.Join(location, ", ") + ": " + details
+		}
+		diags.AddAttributeError(
+			path.Root("revision_id"),
+			"Invalid Qwilt CDN Site Configuration",
+			details+"\n\n"+summary,
+		)
+	}
+}
+
+// cancelPubOp - Cancels a publishing operation the resource gave up waiting for on a timeout or an interruption,
+// so it does not block the next apply of the site, unless cancel_on_timeout is false. The outcome is reported as a warning.
+// Operations left after another error, such as a transient API failure, keep running.
+func (r *siteActivationResource) cancelPubOp(ctx context.Context, plan cdnmodel.SiteActivation, publishId string, waitErr error, diags *diag.Diagnostics) {
+	if !gaveUpWaiting(ctx, waitErr) {
+		return
+	}
+	siteId := plan.SiteId.ValueString()
+	if !plan.CancelOnTimeout.IsNull() && !plan.CancelOnTimeout.ValueBool() {
+		diags.AddWarning(
+			"Qwilt CDN Site Publish operation left running",
+			fmt.Sprintf("The publishing operation %s of Qwilt CDN Site %s was not canceled, as cancel_on_timeout is false. "+
+				"Another publishing operation of the site cannot start until it ends.", publishId, siteId),
+		)
+		return
+	}
+
+	// The operation context may be done already, on a timeout or an interruption
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), PUBLISH_CANCEL_TIMEOUT)
+	defer cancel()
+
+	tflog.Info(ctx, "siteActivationResource: canceling publish-id: "+publishId)
+	err := r.client.Cancel(cancelCtx, siteId, publishId)
+	if err != nil {
+		diags.AddWarning(
+			"Error Canceling Qwilt CDN Site Publish operation",
+			fmt.Sprintf("Could not cancel the publishing operation %s of Qwilt CDN Site %s, it may still be running: %s", publishId, siteId, err.Error()),
+		)
+		return
+	}
+	diags.AddWarning(
+		"Canceled Qwilt CDN Site Publish operation",
+		fmt.Sprintf("The publishing operation %s of Qwilt CDN Site %s was canceled.", publishId, siteId),
+	)
+}
+
+// gaveUpWaiting - Reports whether waiting for a publishing operation ended on a timeout or an interruption
+func gaveUpWaiting(ctx context.Context, waitErr error) bool {
+	return ctx.Err() != nil || errors.Is(waitErr, context.DeadlineExceeded) || errors.Is(waitErr, context.Canceled)
+}
+
+// isActiveRevision - Checks that the revision of the plan is the active one of the site, the one the Republish API
+// pushes again. Returns false with an error diagnostic when it is not. The site is read bypassing the response cache,
+// a cached answer could miss a publish made since.
+func (r *siteActivationResource) isActiveRevision(ctx context.Context, plan cdnmodel.SiteActivation, diags *diag.Diagnostics) bool {
+	siteResp, err := r.client.GetSiteUncached(ctx, plan.SiteId.ValueString(), r.target, true, false)
+	if err != nil {
+		diags.AddError(
+			"Error Getting active revision for Qwilt CDN Site",
+			"Could not get active revision for Qwilt CDN Site, unexpected error: "+err.Error(),
+		)
+		return false
+	}
+
+	var activeRevisionId string
+	if siteResp.ActiveAndLastPublishingOperation != nil && siteResp.ActiveAndLastPublishingOperation.Active != nil &&
+		siteResp.ActiveAndLastPublishingOperation.Active.OperationType != api.OPERATION_TYPE_UNPUBLISH {
+		activeRevisionId = siteResp.ActiveAndLastPublishingOperation.Active.RevisionId
+	}
+	if activeRevisionId != plan.RevisionId.ValueString() {
+		diags.AddError(
+			"Error Republishing Qwilt CDN Site",
+			fmt.Sprintf("Could not republish revision %s of Qwilt CDN Site %s, as the active revision of the site is %q. "+
+				"Run terraform refresh, or change revision_id to publish it again.", plan.RevisionId.ValueString(), plan.SiteId.ValueString(), activeRevisionId),
+		)
+		return false
+	}
+	return true
+}
+
+// UpgradeState upgrades the state of the earlier versions of the resource.
+func (r *siteActivationResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored validators_err_details as a raw JSON string
+		0: {StateUpgrader: upgradeSiteActivationStateV0},
+	}
+}
+
+func upgradeSiteActivationStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	rawState := map[string]any{}
+	if err := json.Unmarshal(req.RawState.JSON, &rawState); err != nil {
+		resp.Diagnostics.AddError(
+			"Error Upgrading Qwilt CDN Site Activation State",
+			"Could not decode the state of the Qwilt CDN Site Activation: "+err.Error(),
+		)
+		return
+	}
+
+	details, _ := rawState["validators_err_details"].(string)
+	validatorErrors := []map[string]string{}
+	for _, validatorError := range cdnclient.ParseValidatorErrors(json.RawMessage(details)) {
+		validatorErrors = append(validatorErrors, map[string]string{
+			"validator": validatorError.Validator,
+			"host":      validatorError.Host,
+			"path":      validatorError.Path,
+			"message":   validatorError.Message,
+		})
+	}
+	rawState["validators_err_details"] = validatorErrors
+	tflog.Debug(ctx, "siteActivationResource: upgraded state from version 0", map[string]any{"validator_errors": len(validatorErrors)})
+
+	upgradedState, err := json.Marshal(rawState)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Upgrading Qwilt CDN Site Activation State",
+			"Could not encode the state of the Qwilt CDN Site Activation: "+err.Error(),
+		)
+		return
+	}
+	// Attributes missing from the earlier state, such as status_line, are set to null
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgradedState}
 }
 
 func (r *siteActivationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -616,7 +993,7 @@ func (r *siteActivationResource) ImportState(ctx context.Context, req resource.I
 		site_id = idParts[0]
 
 		//try to get latest or active revision
-		siteResp, err := r.client.GetSite(idParts[0], r.target, true, false)
+		siteResp, err := r.client.GetSite(ctx, idParts[0], r.target, true, false)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting latest/active revision for Qwilt CDN Site",
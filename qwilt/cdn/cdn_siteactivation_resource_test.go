@@ -9,11 +9,24 @@ package cdn
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
+	cdnmodel "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/model"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/internal/fakeapi"
 	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/stretchr/testify/assert"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -24,6 +37,7 @@ func TestSiteActivationResource(t *testing.T) {
 
 	//set this after running script generate_dev_overrides.sh
 	SetDevOverrides()
+	setupTestApi(t)
 
 	tfBinaryPath := "terraform"
 
@@ -134,7 +148,7 @@ func TestSiteActivationResource(t *testing.T) {
 	assert.Equal(t, certId, siteActivationState.AttributeValues["certificate_id"])
 	assert.Nil(t, siteActivationState.AttributeValues["csr_id"])
 	assert.Equal(t, "ga", siteActivationState.AttributeValues["target"])
-	assert.Equal(t, "null", siteActivationState.AttributeValues["validators_err_details"])
+	assert.Empty(t, siteActivationState.AttributeValues["validators_err_details"])
 	assert.Equal(t, "Publish", siteActivationState.AttributeValues["operation_type"])
 
 	_, ok := siteActivationState.AttributeValues["creation_time_milli"]
@@ -164,7 +178,7 @@ func TestSiteActivationResource(t *testing.T) {
 	assert.True(t, publishCompleted)
 	assert.Equal(t, "Success", siteActivationState.AttributeValues["publish_status"])
 	assert.Equal(t, "Accepted", siteActivationState.AttributeValues["publish_acceptance_status"])
-	assert.Equal(t, "null", siteActivationState.AttributeValues["validators_err_details"])
+	assert.Empty(t, siteActivationState.AttributeValues["validators_err_details"])
 
 	//prepare for import, remove existing resources from state
 	err = tf.StateRm(context.Background(), "qwilt_cdn_site_activation.test")
@@ -257,3 +271,206 @@ func TestSiteActivationResource(t *testing.T) {
 	//	log.Fatalf("Error destroying Terraform-managed infrastructure: %s", err)
 	//}
 }
+
+func TestSiteActivationResourceUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := NewSiteActivationResource().(*siteActivationResource)
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id":"s1:p1","site_id":"s1","publish_id":"p1","validators_err_details":"[{\"message\":\"invalid origin\"}]"}`),
+		},
+	}
+	resp := resource.UpgradeStateResponse{}
+	r.UpgradeState(ctx)[0].StateUpgrader(ctx, req, &resp)
+	assert.False(t, resp.Diagnostics.HasError())
+
+	// The upgraded state matches the current schema
+	_, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	assert.Nil(t, err)
+
+	upgraded := map[string]any{}
+	assert.Nil(t, json.Unmarshal(resp.DynamicValue.JSON, &upgraded))
+	assert.Equal(t, "s1", upgraded["site_id"])
+	assert.Equal(t, []any{map[string]any{"validator": "", "host": "", "path": "", "message": "invalid origin"}}, upgraded["validators_err_details"])
+}
+
+func TestSiteActivationValidationDiagnostics(t *testing.T) {
+	plan := cdnmodel.SiteActivation{SiteId: types.StringValue("s1"), RevisionId: types.StringValue("r1")}
+	pubOp := &api.PubOp{
+		PublishAcceptanceStatus: "Invalid",
+		StatusLine:              []string{"Validation failed"},
+		ValidatorsErrDetails: json.RawMessage(`[
+			{"validator":"origin","host":"www.example.com","path":"/hosts/0/host-metadata/origin","message":"invalid origin"},
+			{"host":"cdn.example.com","message":"unknown metadata"}]`),
+	}
+
+	diags := diag.Diagnostics{}
+	addValidationDiagnostics(plan, pubOp, &diags)
+	assert.Equal(t, 2, diags.ErrorsCount())
+	assert.Equal(t, path.Root("revision_id"), diags[0].(diag.DiagnosticWithPath).Path())
+	assert.Contains(t, diags[0].Detail(), "In host www.example.com, host_index path /hosts/0/host-metadata/origin, validator origin: invalid origin")
+	assert.Contains(t, diags[0].Detail(), "Publish failed for Qwilt CDN Site s1, revision r1. Acceptance Status: Invalid. Status line: Validation failed.")
+	assert.Contains(t, diags[1].Detail(), "In host cdn.example.com: unknown metadata")
+
+	// Without details, a single error reports the status
+	pubOp = &api.PubOp{PublishAcceptanceStatus: "Dismissed"}
+	diags = diag.Diagnostics{}
+	addValidationDiagnostics(plan, pubOp, &diags)
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, "Publish failed for Qwilt CDN Site s1, revision r1. Acceptance Status: Dismissed.", diags[0].Detail())
+
+	// An unrecognised payload is reported raw
+	pubOp = &api.PubOp{PublishAcceptanceStatus: "Invalid", ValidatorsErrDetails: json.RawMessage(`{"code":42,"reason":"quota exceeded"}`)}
+	diags = diag.Diagnostics{}
+	addValidationDiagnostics(plan, pubOp, &diags)
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, path.Root("revision_id"), diags[0].(diag.DiagnosticWithPath).Path())
+	assert.Contains(t, diags[0].Detail(), `{"code":42,"reason":"quota exceeded"}`)
+
+	// And so are details holding no failure
+	pubOp = &api.PubOp{PublishAcceptanceStatus: "Invalid", ValidatorsErrDetails: json.RawMessage(`[]`)}
+	diags = diag.Diagnostics{}
+	addValidationDiagnostics(plan, pubOp, &diags)
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, "Validation details: []\n\nPublish failed for Qwilt CDN Site s1, revision r1. Acceptance Status: Invalid.", diags[0].Detail())
+}
+
+func TestSiteActivationWaitsForConcurrentPubOp(t *testing.T) {
+	ctx := context.Background()
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	c, err := cdnclient.NewClient("dev", "", "", "fake-key")
+	assert.Nil(t, err)
+	for service, baseUrl := range server.Endpoints() {
+		c.SetEndpoint(service, baseUrl)
+	}
+	c.PollInterval = time.Millisecond
+	r := siteActivationResource{client: cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, c), target: cdnclient.TARGET_GA, siteLocks: NewSiteLocks()}
+
+	site, err := r.client.CreateSite(ctx, api.SiteCreateRequest{SiteName: "fake site"})
+	assert.Nil(t, err)
+	config, err := r.client.CreateSiteConfig(ctx, site.SiteId, api.SiteConfigAddRequest{HostIndex: json.RawMessage(`{"hosts":[]}`)})
+	assert.Nil(t, err)
+
+	// A publishing operation started outside of Terraform is still in progress
+	other, err := r.client.Publish(ctx, site.SiteId, config.RevisionId, cdnclient.TARGET_GA)
+	assert.Nil(t, err)
+	publish := func() (*api.PubOp, error) {
+		return r.client.Publish(ctx, site.SiteId, config.RevisionId, cdnclient.TARGET_GA)
+	}
+	_, err = publish()
+	assert.True(t, cdnclient.IsConflict(err))
+
+	diags := diag.Diagnostics{}
+	pubOp, err := r.startPubOp(ctx, site.SiteId, cdnclient.TARGET_GA, time.Now().Add(time.Minute), &diags, publish)
+	assert.Nil(t, err)
+	assert.NotEqual(t, other.PublishId, pubOp.PublishId)
+	assert.Equal(t, 1, diags.WarningsCount())
+	assert.Contains(t, diags[0].Detail(), "Publishing operation "+other.PublishId+" of Qwilt CDN Site "+site.SiteId)
+	assert.Contains(t, diags[0].Detail(), "It ended with status Success")
+
+	// Without an operation in progress, start is called once
+	_, err = r.client.GetAndWaitForPubOpCompletion(ctx, site.SiteId, pubOp.PublishId, time.Minute)
+	assert.Nil(t, err)
+	diags = diag.Diagnostics{}
+	var calls int
+	_, err = r.startPubOp(ctx, site.SiteId, cdnclient.TARGET_GA, time.Now().Add(time.Minute), &diags, func() (*api.PubOp, error) {
+		calls++
+		return publish()
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, diags)
+}
+
+func TestSiteActivationStartsPubOpOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter)
+	}{
+		{name: "rejected", handler: func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"invalid revision"}`))
+		}},
+		{name: "timeout", handler: func(w http.ResponseWriter) {
+			// The request reached the API, the client gives up before the answer
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"publishId":"1"}`))
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posts, gets atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					posts.Add(1)
+					test.handler(w)
+					return
+				}
+				gets.Add(1)
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			c, err := cdnclient.NewClient("dev", "", "", "fake-key")
+			assert.Nil(t, err)
+			c.SetEndpoint(api.SITES_HOSTNAME, server.URL)
+			c.HTTPClient.Timeout = 50 * time.Millisecond
+			r := siteActivationResource{client: cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, c), target: cdnclient.TARGET_GA, siteLocks: NewSiteLocks()}
+
+			ctx := context.Background()
+			diags := diag.Diagnostics{}
+			_, err = r.startPubOp(ctx, "s1", cdnclient.TARGET_GA, time.Now().Add(time.Minute), &diags, func() (*api.PubOp, error) {
+				return r.client.Publish(ctx, "s1", "r1", cdnclient.TARGET_GA)
+			})
+			assert.NotNil(t, err)
+			assert.Equal(t, int32(1), posts.Load())
+			assert.Equal(t, int32(0), gets.Load())
+			assert.Empty(t, diags)
+		})
+	}
+}
+
+func TestSiteActivationCancelsOnlyWhenGivingUp(t *testing.T) {
+	ctx := context.Background()
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	c, err := cdnclient.NewClient("dev", "", "", "fake-key")
+	assert.Nil(t, err)
+	for service, baseUrl := range server.Endpoints() {
+		c.SetEndpoint(service, baseUrl)
+	}
+	r := siteActivationResource{client: cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, c), target: cdnclient.TARGET_GA, siteLocks: NewSiteLocks()}
+
+	site, err := r.client.CreateSite(ctx, api.SiteCreateRequest{SiteName: "fake site"})
+	assert.Nil(t, err)
+	config, err := r.client.CreateSiteConfig(ctx, site.SiteId, api.SiteConfigAddRequest{HostIndex: json.RawMessage(`{"hosts":[]}`)})
+	assert.Nil(t, err)
+	pubOp, err := r.client.Publish(ctx, site.SiteId, config.RevisionId, cdnclient.TARGET_GA)
+	assert.Nil(t, err)
+	plan := cdnmodel.SiteActivation{SiteId: types.StringValue(site.SiteId), CancelOnTimeout: types.BoolNull()}
+
+	// A transient failure leaves the operation running
+	diags := diag.Diagnostics{}
+	r.cancelPubOp(ctx, plan, pubOp.PublishId, fmt.Errorf("connection reset by peer"), &diags)
+	assert.Empty(t, diags)
+	_, err = r.client.Publish(ctx, site.SiteId, config.RevisionId, cdnclient.TARGET_GA)
+	assert.True(t, cdnclient.IsConflict(err))
+
+	// Giving up on a timeout cancels it
+	r.cancelPubOp(ctx, plan, pubOp.PublishId, fmt.Errorf("waiting: %w", context.DeadlineExceeded), &diags)
+	assert.Equal(t, 1, diags.WarningsCount())
+	assert.Equal(t, "Canceled Qwilt CDN Site Publish operation", diags[0].Summary())
+
+	// And so does an interruption, whatever the error
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.True(t, gaveUpWaiting(canceledCtx, fmt.Errorf("boom")))
+	assert.False(t, gaveUpWaiting(ctx, fmt.Errorf("boom")))
+}
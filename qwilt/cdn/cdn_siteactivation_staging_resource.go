@@ -15,9 +15,10 @@ import (
 )
 
 var (
-	_ resource.Resource                = &siteActivationStagingResource{}
-	_ resource.ResourceWithConfigure   = &siteActivationStagingResource{}
-	_ resource.ResourceWithImportState = &siteActivationStagingResource{}
+	_ resource.Resource                 = &siteActivationStagingResource{}
+	_ resource.ResourceWithConfigure    = &siteActivationStagingResource{}
+	_ resource.ResourceWithImportState  = &siteActivationStagingResource{}
+	_ resource.ResourceWithUpgradeState = &siteActivationStagingResource{}
 )
 
 func NewSiteActivationStagingResource() resource.Resource {
@@ -77,3 +78,8 @@ func (r *siteActivationStagingResource) Configure(ctx context.Context, req resou
 func (r *siteActivationStagingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	r.siteActivationResource.ImportState(ctx, req, resp)
 }
+
+// UpgradeState upgrades the state of the earlier versions of the resource.
+func (r *siteActivationStagingResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return r.siteActivationResource.UpgradeState(ctx)
+}
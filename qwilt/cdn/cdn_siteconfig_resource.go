@@ -105,7 +105,7 @@ func (r *siteConfigResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 
 	// Create new site
-	siteResp, err := r.client.CreateSiteConfig(plan.SiteId.ValueString(), siteCreate)
+	siteResp, err := r.client.CreateSiteConfig(ctx, plan.SiteId.ValueString(), siteCreate)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating Qwilt CDN Site",
@@ -145,8 +145,13 @@ func (r *siteConfigResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Get refreshed site value from CDN
-	siteResp, err := r.client.GetSiteConfig(state.SiteId.ValueString(), state.RevisionId.ValueString(), false)
+	siteResp, err := r.client.GetSiteConfig(ctx, state.SiteId.ValueString(), state.RevisionId.ValueString(), false)
 	if err != nil {
+		if cdnclient.IsNotFound(err) {
+			tflog.Warn(ctx, "siteConfigResource: site configuration not found, removing from state", map[string]any{"site_id": state.SiteId.ValueString(), "revision_id": state.RevisionId.ValueString()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error Reading Qwilt CDN Site Configuration",
 			"Could not read Qwilt CDN Site ID "+state.SiteId.ValueString()+": "+err.Error(),
@@ -190,7 +195,7 @@ func (r *siteConfigResource) Update(ctx context.Context, req resource.UpdateRequ
 	}
 
 	// Create new site - update is not supported for SiteConfiguration
-	siteResp, err := r.client.CreateSiteConfig(plan.SiteId.ValueString(), siteCreate)
+	siteResp, err := r.client.CreateSiteConfig(ctx, plan.SiteId.ValueString(), siteCreate)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Creating Qwilt CDN Site",
@@ -239,18 +244,18 @@ func (r *siteConfigResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, client)
+	r.client = providerData.Facade
 }
 
 func (r *siteConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -274,7 +279,7 @@ func (r *siteConfigResource) ImportState(ctx context.Context, req resource.Impor
 		tflog.Info(ctx, "import site "+site_id+" implicitly")
 
 		//try to get latest or active revision
-		siteResp, err := r.client.GetSite(idParts[0], "ga", false, false)
+		siteResp, err := r.client.GetSite(ctx, idParts[0], "ga", false, false)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Getting latest/active revision for Qwilt CDN Site",
@@ -296,7 +301,7 @@ func (r *siteConfigResource) ImportState(ctx context.Context, req resource.Impor
 
 			//if we couldnt get active or latest, try to import the latest revision_num
 			//try to get latest or active revision
-			siteConfigsResp, err := r.client.GetSiteConfigs(idParts[0], true)
+			siteConfigsResp, err := r.client.GetSiteConfigs(ctx, idParts[0], true)
 			if err != nil || len(siteConfigsResp) == 0 {
 				resp.Diagnostics.AddError(
 					"Error Getting site configuration revisions for Qwilt CDN Site",
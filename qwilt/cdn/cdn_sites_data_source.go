@@ -10,7 +10,6 @@ package cdn
 import (
 	"context"
 	"fmt"
-	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
 	cdnmodel "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/model"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -249,15 +248,6 @@ func (d *qwiltSitesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 func (d *qwiltSitesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state cdnmodel.QwiltSitesDataSourceModel
 
-	sites, err := d.client.GetSites(true, true)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Qwilt Sites",
-			err.Error(),
-		)
-		return
-	}
-
 	// Get state
 	diags := resp.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -300,8 +290,19 @@ func (d *qwiltSitesDataSource) Read(ctx context.Context, _ datasource.ReadReques
 	}
 
 	// Map response body to model
-	// Get site(s)
-	for _, site := range sites {
+	// Get site(s), stopping once the filtered site is found
+	sites, err := d.client.ListSites(ctx, true, true)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Qwilt Sites",
+			err.Error(),
+		)
+		return
+	}
+	defer sites.Close()
+
+	for sites.Next() {
+		site := sites.Value()
 		if siteIdFilter != "all" && site.SiteId != siteIdFilter {
 			continue
 		}
@@ -324,11 +325,21 @@ func (d *qwiltSitesDataSource) Read(ctx context.Context, _ datasource.ReadReques
 		}
 
 		state.Site = append(state.Site, siteState)
+		if siteIdFilter != "all" {
+			break
+		}
+	}
+	if err := sites.Err(); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Qwilt Sites",
+			err.Error(),
+		)
+		return
 	}
 
 	// Get revision(s)
 	if siteIdFilter != "all" {
-		siteConfigs, err := d.client.GetSiteConfigs(siteIdFilter, truncateHostIndex)
+		siteConfigs, err := d.client.GetSiteConfigs(ctx, siteIdFilter, truncateHostIndex)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				fmt.Sprintf("Unable to Read Qwilt Site Config for SiteId %s", siteIdFilter),
@@ -359,7 +370,7 @@ func (d *qwiltSitesDataSource) Read(ctx context.Context, _ datasource.ReadReques
 
 	// Get publishing operation(s)
 	if siteIdFilter != "all" {
-		pubOps, err := d.client.GetPubOps(siteIdFilter, false, "")
+		pubOps, err := d.client.GetPubOps(ctx, siteIdFilter, false, "")
 		if err != nil {
 			resp.Diagnostics.AddError(
 				fmt.Sprintf("Unable to Read Qwilt Publishing Operations for SiteId %s", siteIdFilter),
@@ -407,15 +418,15 @@ func (d *qwiltSitesDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*cdnclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *cdnclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cdn.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, client)
+	d.client = providerData.Facade
 }
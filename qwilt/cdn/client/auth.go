@@ -9,24 +9,17 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // SignIn - Get a new token for user
-func (c *Client) SignIn() (*AuthResponse, error) {
+func (c *Client) SignIn(ctx context.Context) (*AuthResponse, error) {
 	if c.Auth.Username == "" || c.Auth.Password == "" {
 		return nil, fmt.Errorf("Please define the username and password to authenticate")
 	}
 
-	rb, err := json.Marshal(c.Auth)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/login", c.authEndpoint), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "GET", fmt.Sprintf("%s/login", c.authEndpoint), c.Auth)
 	if err != nil {
 		return nil, err
 	}
@@ -42,3 +35,37 @@ func (c *Client) SignIn() (*AuthResponse, error) {
 
 	return &ar, nil
 }
+
+// token - Returns the session token, signing in on first use
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.Token != "" {
+		return c.Token, nil
+	}
+	return c.signInLocked(ctx)
+}
+
+// refreshToken - Signs in again after the API rejected staleToken.
+// Concurrent callers that hold the same stale token share a single sign-in.
+func (c *Client) refreshToken(ctx context.Context, staleToken string) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.Token != staleToken {
+		// Another request already refreshed the token
+		return c.Token, nil
+	}
+	return c.signInLocked(ctx)
+}
+
+// signInLocked - Signs in and stores the new token, authMu must be held
+func (c *Client) signInLocked(ctx context.Context) (string, error) {
+	ar, err := c.SignIn(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.Token = ar.Token
+	return c.Token, nil
+}
@@ -0,0 +1,73 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazySignInAndSharedTokenRefresh(t *testing.T) {
+	var logins int32
+	var currentToken atomic.Value
+	currentToken.Store("")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		token := fmt.Sprintf("token-%d", atomic.AddInt32(&logins, 1))
+		currentToken.Store(token)
+		http.SetCookie(w, &http.Cookie{Name: "cqloudLoginToken", Value: token})
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+currentToken.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := newRetryTestClient()
+	c.XApiToken = ""
+	c.Auth = AuthStruct{Username: "user", Password: "pass"}
+	c.authEndpoint = server.URL
+	assert.Equal(t, int32(0), atomic.LoadInt32(&logins))
+
+	doGet := func() error {
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", server.URL+"/api", nil)
+		_, err := c.doRequest(req)
+		return err
+	}
+
+	// First request signs in
+	assert.Nil(t, doGet())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+
+	// Expire the token on the server side, concurrent requests share one refresh
+	currentToken.Store("expired")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, doGet())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&logins))
+}
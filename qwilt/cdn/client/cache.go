@@ -0,0 +1,156 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const DEFAULT_RESPONSE_CACHE_TTL = 30 * time.Second
+
+// responseCache - Caches the responses of single object lookups for the lifetime of a Client,
+// so the resources and data sources of one run share them.
+// Identical concurrent lookups share a single request, and any mutating request invalidates
+// the cached responses of the same object, its parents and its children.
+type responseCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	entries  map[string]cacheEntry
+	inflight map[string]*cacheCall
+	// generation changes on every mutation, a lookup started before a mutation is not cached
+	generation uint64
+}
+
+type cacheEntry struct {
+	host    string
+	path    string
+	body    []byte
+	expires time.Time
+}
+
+type cacheCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:      ttl,
+		entries:  map[string]cacheEntry{},
+		inflight: map[string]*cacheCall{},
+	}
+}
+
+// SetResponseCacheTTL - Sets how long object lookups are cached. 0 disables the cache.
+func (c *Client) SetResponseCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = newResponseCache(ttl)
+}
+
+// doCachedRequest - Performs a GET request through the response cache
+func (c *Client) doCachedRequest(req *http.Request) ([]byte, error) {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return c.doRequest(req)
+	}
+	return c.cache.get(req, c.doRequest)
+}
+
+// invalidateCache - Drops the cached responses related to the target of a mutating request
+func (c *Client) invalidateCache(req *http.Request) {
+	if c.cache == nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return
+	}
+	c.cache.invalidate(req.URL)
+}
+
+func (rc *responseCache) get(req *http.Request, fetch func(*http.Request) ([]byte, error)) ([]byte, error) {
+	key := req.URL.String()
+
+	rc.mu.Lock()
+	if entry, ok := rc.entries[key]; ok && time.Now().Before(entry.expires) {
+		rc.mu.Unlock()
+		tflog.Trace(req.Context(), "Qwilt API response served from cache", map[string]any{"url": key})
+		return entry.body, nil
+	}
+	if call, ok := rc.inflight[key]; ok {
+		rc.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if isContextError(call.err) && req.Context().Err() == nil {
+			// The shared request was canceled by its own caller, not by this one
+			return fetch(req)
+		}
+		return call.body, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	rc.inflight[key] = call
+	generation := rc.generation
+	rc.mu.Unlock()
+
+	call.body, call.err = fetch(req)
+
+	rc.mu.Lock()
+	delete(rc.inflight, key)
+	if call.err == nil && rc.generation == generation {
+		rc.entries[key] = cacheEntry{
+			host:    req.URL.Host,
+			path:    cleanPath(req.URL.Path),
+			body:    call.body,
+			expires: time.Now().Add(rc.ttl),
+		}
+	}
+	rc.mu.Unlock()
+	close(call.done)
+
+	return call.body, call.err
+}
+
+func (rc *responseCache) invalidate(target *url.URL) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.generation++
+	targetPath := cleanPath(target.Path)
+	for key, entry := range rc.entries {
+		if entry.host == target.Host && relatedPaths(entry.path, targetPath) {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// cleanPath - Normalizes a path, some URLs are built with a double slash
+func cleanPath(p string) string {
+	return strings.TrimSuffix(path.Clean("/"+p), "/")
+}
+
+// relatedPaths - Reports whether a change to one path may change the other, e.g. publishing
+// (POST /sites/1/publishing-operations) changes the site (/sites/1)
+func relatedPaths(a string, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
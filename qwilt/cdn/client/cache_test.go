@@ -0,0 +1,136 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCacheTestServer(t *testing.T, delay time.Duration) (*httptest.Server, *atomic.Int32) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+			time.Sleep(delay)
+		}
+		w.Write([]byte(`{"siteId":"1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &gets
+}
+
+func cachedGet(c *Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.doCachedRequest(req)
+}
+
+func TestResponseCacheHitAndExpiry(t *testing.T) {
+	server, gets := newCacheTestServer(t, 0)
+	c := newRetryTestClient()
+	c.SetResponseCacheTTL(50 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		body, err := cachedGet(c, server.URL+"/api/v2/sites/1")
+		assert.Nil(t, err)
+		assert.Equal(t, `{"siteId":"1"}`, string(body))
+	}
+	assert.Equal(t, int32(1), gets.Load())
+
+	// Another object is not served from the cache
+	_, err := cachedGet(c, server.URL+"/api/v2/sites/2")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), gets.Load())
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = cachedGet(c, server.URL+"/api/v2/sites/1")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), gets.Load())
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	server, gets := newCacheTestServer(t, 0)
+	c := newRetryTestClient()
+	c.SetResponseCacheTTL(0)
+
+	cachedGet(c, server.URL+"/api/v2/sites/1")
+	cachedGet(c, server.URL+"/api/v2/sites/1")
+	assert.Equal(t, int32(2), gets.Load())
+}
+
+func TestResponseCacheSingleFlight(t *testing.T) {
+	server, gets := newCacheTestServer(t, 50*time.Millisecond)
+	c := newRetryTestClient()
+	c.SetResponseCacheTTL(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := cachedGet(c, server.URL+"/api/v2/sites/1")
+			assert.Nil(t, err)
+			assert.Equal(t, `{"siteId":"1"}`, string(body))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), gets.Load())
+}
+
+func TestResponseCacheInvalidatedByMutation(t *testing.T) {
+	server, gets := newCacheTestServer(t, 0)
+	c := newRetryTestClient()
+	c.SetResponseCacheTTL(time.Minute)
+
+	cachedGet(c, server.URL+"/api/v2/sites/1")
+	cachedGet(c, server.URL+"/api/v2/sites/10")
+	assert.Equal(t, int32(2), gets.Load())
+
+	// Publishing a site changes the site itself
+	req, _ := http.NewRequest("POST", server.URL+"//api/v2/sites/1/publishing-operations", strings.NewReader(`{}`))
+	_, err := c.doRequest(req)
+	assert.Nil(t, err)
+
+	cachedGet(c, server.URL+"/api/v2/sites/1")
+	assert.Equal(t, int32(3), gets.Load())
+
+	// A sibling whose ID shares a prefix is kept
+	cachedGet(c, server.URL+"/api/v2/sites/10")
+	assert.Equal(t, int32(3), gets.Load())
+}
+
+func TestGetSiteUncached(t *testing.T) {
+	server, gets := newCacheTestServer(t, 0)
+	c := newRetryTestClient()
+	c.SetResponseCacheTTL(time.Minute)
+	sites := &SiteClient{Client: c, apiEndpoint: server.URL}
+
+	_, err := sites.GetSite(context.Background(), "1", "", true, false)
+	assert.Nil(t, err)
+	_, err = sites.GetSite(context.Background(), "1", "", true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), gets.Load())
+
+	// The uncached read always reaches the API
+	site, err := sites.GetSiteUncached(context.Background(), "1", "", true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", site.SiteId)
+	assert.Equal(t, int32(2), gets.Load())
+}
@@ -0,0 +1,277 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cassettes record the HTTP exchanges of a test run so they can be replayed later without the Qwilt APIs.
+// The test harness creates the cassette and wraps the transport of the client with it, the provider never does.
+const CASSETTE_MODE_RECORD = "record"
+const CASSETTE_MODE_REPLAY = "replay"
+
+// ErrCassetteMismatch - A replayed request that the cassette has no answer for. The cassette no longer matches
+// the test, so it fails at once rather than being retried.
+var ErrCassetteMismatch = errors.New("request does not match the cassette")
+
+// CassetteHeader - The first line of a cassette
+type CassetteHeader struct {
+	// Seed lets tests generate the same names and inputs when recording and replaying
+	Seed int64 `json:"seed"`
+}
+
+// cassetteInteraction - One recorded request and its response, one per line after the header
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Cassette - The recorded exchanges of one test. Terraform configures the provider several times during
+// a test, the transports of all these clients share the cassette so a replay continues where the previous one stopped.
+type Cassette struct {
+	Mode string
+	Seed int64
+
+	mu           sync.Mutex
+	path         string
+	interactions []cassetteInteraction
+	replayed     map[int]bool
+}
+
+// NewCassette - Starts a cassette for a test run. Recording starts a new cassette file, replaying loads an existing one.
+func NewCassette(mode string, path string) (*Cassette, error) {
+	cassette := &Cassette{Mode: mode, path: path, replayed: map[int]bool{}}
+	switch mode {
+	case CASSETTE_MODE_RECORD:
+		header := CassetteHeader{Seed: time.Now().UnixNano()}
+		line, err := json.Marshal(header)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, append(line, '\n'), 0644); err != nil {
+			return nil, err
+		}
+		cassette.Seed = header.Seed
+		return cassette, nil
+	case CASSETTE_MODE_REPLAY:
+		header, interactions, err := readCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		cassette.Seed = header.Seed
+		cassette.interactions = interactions
+		return cassette, nil
+	}
+	return nil, fmt.Errorf("invalid cassette mode %q, expected %q or %q", mode, CASSETTE_MODE_RECORD, CASSETTE_MODE_REPLAY)
+}
+
+// Transport - Wraps a transport to record its exchanges to the cassette, or replays them from the cassette
+// without ever calling next
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	if c.Mode == CASSETTE_MODE_RECORD {
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return &cassetteRecorder{cassette: c, next: next}
+	}
+	return &cassettePlayer{cassette: c}
+}
+
+func readCassette(path string) (*CassetteHeader, []cassetteInteraction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open cassette: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header := CassetteHeader{}
+	interactions := []cassetteInteraction{}
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var decodeErr error
+			if lineNum == 1 {
+				decodeErr = json.Unmarshal(line, &header)
+			} else {
+				interaction := cassetteInteraction{}
+				decodeErr = json.Unmarshal(line, &interaction)
+				interactions = append(interactions, interaction)
+			}
+			if decodeErr != nil {
+				return nil, nil, fmt.Errorf("invalid cassette %s line %d: %w", path, lineNum, decodeErr)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return &header, interactions, nil
+}
+
+// appendLine - Appends a JSON line to a file
+func appendLine(path string, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// scrubHeaders - Flattens the headers for the cassette, masking the credentials but keeping cookie names
+func scrubHeaders(header http.Header) map[string]string {
+	headers := redactHeaders(header)
+	if cookies := header.Values("Set-Cookie"); len(cookies) > 0 {
+		scrubbed := []string{}
+		for _, cookie := range cookies {
+			name, _, _ := strings.Cut(cookie, "=")
+			scrubbed = append(scrubbed, name+"="+redacted)
+		}
+		headers["Set-Cookie"] = strings.Join(scrubbed, ", ")
+	}
+	return headers
+}
+
+// scrubBody - Masks the passwords and private keys of a body
+func scrubBody(body []byte) string {
+	return sensitiveBodyFields.ReplaceAllString(string(body), `$1"`+redacted+`"`)
+}
+
+// cassetteRecorder - Sends requests and appends every exchange to the cassette
+type cassetteRecorder struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	interaction := cassetteInteraction{
+		Request: cassetteRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: scrubHeaders(req.Header),
+		},
+	}
+	if content, err := requestBody(req); err == nil && content != nil {
+		interaction.Request.Body = scrubBody(content)
+	}
+
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction.Response = cassetteResponse{
+		StatusCode: res.StatusCode,
+		Headers:    scrubHeaders(res.Header),
+		Body:       scrubBody(body),
+	}
+
+	r.cassette.mu.Lock()
+	defer r.cassette.mu.Unlock()
+	if err := appendLine(r.cassette.path, interaction); err != nil {
+		return nil, fmt.Errorf("could not record interaction to cassette: %w", err)
+	}
+	return res, nil
+}
+
+// cassettePlayer - Answers requests with the recorded responses, never reaching the network.
+// A request is answered by the first interaction not replayed yet with the same method, path and query.
+type cassettePlayer struct {
+	cassette *Cassette
+}
+
+func (p *cassettePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := p.cassette
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	match := -1
+	for i, interaction := range c.interactions {
+		if !c.replayed[i] && interaction.Request.Method == req.Method && sameRequestURI(interaction.Request.URL, req) {
+			match = i
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("%w: cassette %s has no recorded interaction left for %s %s", ErrCassetteMismatch, c.path, req.Method, req.URL.RequestURI())
+	}
+	c.replayed[match] = true
+
+	recorded := c.interactions[match].Response
+	res := &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+	for name, value := range recorded.Headers {
+		if name == "Set-Cookie" {
+			for _, cookie := range strings.Split(value, ", ") {
+				res.Header.Add(name, cookie)
+			}
+			continue
+		}
+		res.Header.Set(name, value)
+	}
+	return res, nil
+}
+
+// sameRequestURI - Compares the path and query, the host depends on the environment the cassette was recorded in
+func sameRequestURI(recordedUrl string, req *http.Request) bool {
+	recorded, err := url.Parse(recordedUrl)
+	if err != nil {
+		return false
+	}
+	return recorded.RequestURI() == req.URL.RequestURI()
+}
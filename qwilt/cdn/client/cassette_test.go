@@ -0,0 +1,93 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/internal/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripFunc - Adapts a function to http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	// Record against the fake API with one client per call, each signing in with a password
+	server := fakeapi.NewServer()
+	recorder, err := NewCassette(CASSETTE_MODE_RECORD, path)
+	assert.Nil(t, err)
+
+	record := func() *SiteClient {
+		c, _ := NewClient("dev", "user", "secret-password", "")
+		for service, baseUrl := range server.Endpoints() {
+			c.SetEndpoint(service, baseUrl)
+		}
+		c.HTTPClient.Transport = recorder.Transport(nil)
+		return NewSiteClient(api.SITES_HOSTNAME, c)
+	}
+
+	created, err := record().CreateSite(ctx, api.SiteCreateRequest{SiteName: "recorded"})
+	assert.Nil(t, err)
+	_, err = record().UpdateSite(ctx, created.SiteId, api.SiteUpdateRequest{SiteName: "renamed"})
+	assert.Nil(t, err)
+	server.Close()
+
+	content, _ := os.ReadFile(path)
+	assert.NotContains(t, string(content), "secret-password")
+	assert.NotContains(t, string(content), fakeapi.LOGIN_TOKEN)
+
+	// Replay the same calls through clients sharing the cassette, without any server
+	player, err := NewCassette(CASSETTE_MODE_REPLAY, path)
+	assert.Nil(t, err)
+	assert.Equal(t, recorder.Seed, player.Seed)
+
+	var attempts int
+	replay := func() *SiteClient {
+		c, _ := NewClient("dev", "user", "other-password", "")
+		for service, baseUrl := range server.Endpoints() {
+			c.SetEndpoint(service, baseUrl)
+		}
+		c.RetryMaxWait = 0
+		next := player.Transport(nil)
+		c.HTTPClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return next.RoundTrip(req)
+		})
+		return NewSiteClient(api.SITES_HOSTNAME, c)
+	}
+
+	replayed, err := replay().CreateSite(ctx, api.SiteCreateRequest{SiteName: "recorded"})
+	assert.Nil(t, err)
+	assert.Equal(t, created.SiteId, replayed.SiteId)
+
+	updated, err := replay().UpdateSite(ctx, created.SiteId, api.SiteUpdateRequest{SiteName: "renamed"})
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed", updated.SiteName)
+
+	// Every interaction was replayed once, reads don't fall back to an earlier response
+	attempts = 0
+	_, err = replay().GetSite(ctx, created.SiteId, "", false, false)
+	assert.True(t, errors.Is(err, ErrCassetteMismatch), "unexpected error %v", err)
+	assert.ErrorContains(t, err, "no recorded interaction left")
+	assert.Equal(t, 1, attempts, "a mismatch must not be retried")
+}
@@ -9,12 +9,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
-	"github.com/hashicorp/terraform-plugin-framework/types"
 	"net/http"
-	"strings"
 )
 
 type CertificatesClient struct {
@@ -25,41 +24,44 @@ type CertificatesClient struct {
 func NewCertificatesClient(client *Client) *CertificatesClient {
 	c := CertificatesClient{
 		Client:      client,
-		apiEndpoint: client.endpointBuilder.Build("cert-manager"),
+		apiEndpoint: client.endpointBuilder.Build(api.CERT_MANAGER_HOSTNAME),
 	}
 	return &c
 }
 
 // GetCertificates - Returns list of certificates
-func (c *CertificatesClient) GetCertificates(detailed bool) ([]api.Certificate, error) {
+func (c *CertificatesClient) GetCertificates(ctx context.Context, detailed bool) ([]api.Certificate, error) {
+	it, err := c.ListCertificates(ctx, detailed)
+	if err != nil {
+		return nil, err
+	}
+	return collect(it)
+}
+
+// ListCertificates - Returns an iterator over the certificates, decoded while they are downloaded
+func (c *CertificatesClient) ListCertificates(ctx context.Context, detailed bool) (*ListIterator[api.Certificate], error) {
 
 	querystring := ""
 	if detailed == true {
 		querystring = "?detailed=true"
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/certificates%s", c.apiEndpoint, querystring), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := c.doRequest(req)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/certificates%s", c.apiEndpoint, querystring), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	certs := []api.Certificate{}
-	err = json.Unmarshal(body, &certs)
+	body, err := c.doStreamRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return certs, nil
+	return newListIterator[api.Certificate](body), nil
 }
 
 // GetCertificate - Returns certificate details
-func (c *CertificatesClient) GetCertificate(certId types.Int64, detailed bool) (*api.Certificate, error) {
-	if certId.IsNull() {
+func (c *CertificatesClient) GetCertificate(ctx context.Context, certId int64, detailed bool) (*api.Certificate, error) {
+	if certId == 0 {
 		return nil, fmt.Errorf("certId is empty")
 	}
 
@@ -68,12 +70,12 @@ func (c *CertificatesClient) GetCertificate(certId types.Int64, detailed bool) (
 		querystring = "?detailed=true"
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/certificates/%s%s", c.apiEndpoint, certId, querystring), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/certificates/%d%s", c.apiEndpoint, certId, querystring), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
+	body, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -88,13 +90,8 @@ func (c *CertificatesClient) GetCertificate(certId types.Int64, detailed bool) (
 }
 
 // CreateCertificate - Create new certificate
-func (c *CertificatesClient) CreateCertificate(cert api.CertificateCreateRequest) (*api.Certificate, error) {
-	rb, err := json.Marshal(cert)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/certificates", c.apiEndpoint), strings.NewReader(string(rb)))
+func (c *CertificatesClient) CreateCertificate(ctx context.Context, cert api.CertificateCreateRequest) (*api.Certificate, error) {
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/v2/certificates", c.apiEndpoint), cert)
 	if err != nil {
 		return nil, err
 	}
@@ -114,14 +111,9 @@ func (c *CertificatesClient) CreateCertificate(cert api.CertificateCreateRequest
 }
 
 // UpdateCertificate - Update cert details
-func (c *CertificatesClient) UpdateCertificate(certId int64, site api.CertificateUpdateRequest) (*api.Certificate, error) {
-
-	rb, err := json.Marshal(site)
-	if err != nil {
-		return nil, err
-	}
+func (c *CertificatesClient) UpdateCertificate(ctx context.Context, certId int64, cert api.CertificateUpdateRequest) (*api.Certificate, error) {
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v2/certificates/%d", c.apiEndpoint, certId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "PUT", fmt.Sprintf("%s/api/v2/certificates/%d", c.apiEndpoint, certId), cert)
 	if err != nil {
 		return nil, err
 	}
@@ -141,12 +133,12 @@ func (c *CertificatesClient) UpdateCertificate(certId int64, site api.Certificat
 }
 
 // DeleteCertificate - Deletes a certificate
-func (c *CertificatesClient) DeleteCertificate(certId types.Int64) error {
-	if certId.IsNull() {
+func (c *CertificatesClient) DeleteCertificate(ctx context.Context, certId int64) error {
+	if certId == 0 {
 		return fmt.Errorf("certId is empty")
 	}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/certificates/%s", c.apiEndpoint, certId), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/v2/certificates/%d", c.apiEndpoint, certId), nil)
 	if err != nil {
 		return err
 	}
@@ -9,13 +9,13 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
-	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 const CertificateSigningRequestsRoot = "/api/v2/certificate-signing-requests"
@@ -42,23 +42,23 @@ type CertificateSigningRequestClient struct {
 func NewCertificateSigningRequestClient(client *Client) *CertificateSigningRequestClient {
 	c := CertificateSigningRequestClient{
 		Client:      client,
-		apiEndpoint: client.endpointBuilder.Build("cert-manager"),
+		apiEndpoint: client.endpointBuilder.Build(api.CERT_MANAGER_HOSTNAME),
 	}
 	return &c
 }
 
 // GetCertificateSigningRequest - Returns Certificate Signing Request details
-func (c *CertificateSigningRequestClient) GetCertificateSigningRequest(id types.Int64) (*api.CertificateSigningRequest, error) {
-	if id.IsNull() {
+func (c *CertificateSigningRequestClient) GetCertificateSigningRequest(ctx context.Context, id int64) (*api.CertificateSigningRequest, error) {
+	if id == 0 {
 		return nil, fmt.Errorf("csr id is empty")
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", c.apiEndpoint, CertificateSigningRequestsRoot, id), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/%d", c.apiEndpoint, CertificateSigningRequestsRoot, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
+	body, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,8 +72,8 @@ func (c *CertificateSigningRequestClient) GetCertificateSigningRequest(id types.
 	return &certDetail, nil
 }
 
-func (c *CertificateSigningRequestClient) GetChallengeDelegationDomainsListFromCsrId(id int64) (*ChallengeDelegationMap, error) {
-	csr, err := c.GetCertificateSigningRequest(types.Int64Value(id))
+func (c *CertificateSigningRequestClient) GetChallengeDelegationDomainsListFromCsrId(ctx context.Context, id int64) (*ChallengeDelegationMap, error) {
+	csr, err := c.GetCertificateSigningRequest(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -9,13 +9,12 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
-	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 const CertificateTemplatesRoot = "/api/v2/certificate-templates"
@@ -30,44 +29,47 @@ func NewCertificateTemplateClient(client *Client) *CertificateTemplateClient {
 	c := CertificateTemplateClient{
 		Client:      client,
 		csrClient:   NewCertificateSigningRequestClient(client),
-		apiEndpoint: client.endpointBuilder.Build("cert-manager"),
+		apiEndpoint: client.endpointBuilder.Build(api.CERT_MANAGER_HOSTNAME),
 	}
 	return &c
 }
 
 // GetCertificateTemplates - Returns list of Certificate Templates
-func (c *CertificateTemplateClient) GetCertificateTemplates() ([]api.CertificateTemplate, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", c.apiEndpoint, CertificateTemplatesRoot), nil)
+func (c *CertificateTemplateClient) GetCertificateTemplates(ctx context.Context) ([]api.CertificateTemplate, error) {
+	it, err := c.ListCertificateTemplates(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return collect(it)
+}
 
-	body, err := c.doRequest(req)
+// ListCertificateTemplates - Returns an iterator over the Certificate Templates, decoded while they are downloaded
+func (c *CertificateTemplateClient) ListCertificateTemplates(ctx context.Context) (*ListIterator[api.CertificateTemplate], error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s", c.apiEndpoint, CertificateTemplatesRoot), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var certs []api.CertificateTemplate
-	err = json.Unmarshal(body, &certs)
+	body, err := c.doStreamRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return certs, nil
+	return newListIterator[api.CertificateTemplate](body), nil
 }
 
 // GetCertificateTemplate - Returns Certificate Template details
-func (c *CertificateTemplateClient) GetCertificateTemplate(id types.Int64) (*api.CertificateTemplate, error) {
-	if id.IsNull() {
+func (c *CertificateTemplateClient) GetCertificateTemplate(ctx context.Context, id int64) (*api.CertificateTemplate, error) {
+	if id == 0 {
 		return nil, fmt.Errorf("certificate template id is empty")
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", c.apiEndpoint, CertificateTemplatesRoot, id), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s/%d", c.apiEndpoint, CertificateTemplatesRoot, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
+	body, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -82,13 +84,8 @@ func (c *CertificateTemplateClient) GetCertificateTemplate(id types.Int64) (*api
 }
 
 // CreateCertificateTemplate - Create new Certificate Template
-func (c *CertificateTemplateClient) CreateCertificateTemplate(cert api.CertificateTemplateCreateRequest) (*api.CertificateTemplate, error) {
-	rb, err := json.Marshal(cert)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/%s", c.apiEndpoint, CertificateTemplatesRoot), strings.NewReader(string(rb)))
+func (c *CertificateTemplateClient) CreateCertificateTemplate(ctx context.Context, cert api.CertificateTemplateCreateRequest) (*api.CertificateTemplate, error) {
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/%s", c.apiEndpoint, CertificateTemplatesRoot), cert)
 	if err != nil {
 		return nil, err
 	}
@@ -108,12 +105,12 @@ func (c *CertificateTemplateClient) CreateCertificateTemplate(cert api.Certifica
 }
 
 // DeleteCertificateTemplate - Deletes a certificate Template
-func (c *CertificateTemplateClient) DeleteCertificateTemplate(id types.Int64) error {
-	if id.IsNull() {
+func (c *CertificateTemplateClient) DeleteCertificateTemplate(ctx context.Context, id int64) error {
+	if id == 0 {
 		return fmt.Errorf("certificate template id is empty")
 	}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/%s", c.apiEndpoint, CertificateTemplatesRoot, id), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/%s/%d", c.apiEndpoint, CertificateTemplatesRoot, id), nil)
 	if err != nil {
 		return err
 	}
@@ -127,12 +124,12 @@ func (c *CertificateTemplateClient) DeleteCertificateTemplate(id types.Int64) er
 	return nil
 }
 
-func (c *CertificateTemplateClient) GetChallengeDelegationDomainsListFromCertificateTemplateId(id types.Int64) (*ChallengeDelegationMap, error) {
-	if id.IsNull() {
+func (c *CertificateTemplateClient) GetChallengeDelegationDomainsListFromCertificateTemplateId(ctx context.Context, id int64) (*ChallengeDelegationMap, error) {
+	if id == 0 {
 		return nil, fmt.Errorf("certificate template id is empty")
 	}
 
-	certificateTemplate, err := c.GetCertificateTemplate(id)
+	certificateTemplate, err := c.GetCertificateTemplate(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +139,7 @@ func (c *CertificateTemplateClient) GetChallengeDelegationDomainsListFromCertifi
 	}
 
 	lastCsrId := certificateTemplate.CsrIds[len(certificateTemplate.CsrIds)-1]
-	return c.csrClient.GetChallengeDelegationDomainsListFromCsrId(lastCsrId)
+	return c.csrClient.GetChallengeDelegationDomainsListFromCsrId(ctx, lastCsrId)
 }
 
 func (c *CertificateTemplateClient) GetCsrClient() *CertificateSigningRequestClient {
@@ -5,18 +5,41 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Client -
 type Client struct {
-	envType         string
-	HTTPClient      *http.Client
-	Token           string
-	XApiToken       string
-	Auth            AuthStruct
+	envType      string
+	HTTPClient   *http.Client
+	Token        string
+	XApiToken    string
+	Auth         AuthStruct
+	MaxRetries   int
+	RetryMaxWait time.Duration
+	// PollInterval and PollMaxInterval pace the polling of long running operations, see pollWait
+	PollInterval    time.Duration
+	PollMaxInterval time.Duration
 	authEndpoint    string
 	endpointBuilder EndpointBuilder
+	// authMu guards Token so concurrent requests share one sign-in
+	authMu sync.Mutex
+	// limiter throttles the requests of all service clients, nil when unlimited
+	limiter *rateLimiter
+	// cache holds object lookups shared by the resources and data sources, nil when disabled
+	cache *responseCache
+	// gzipRequests enables the gzip encoding of large request bodies, see SetGzipRequests
+	gzipRequests bool
+	// gzipRejected is set once the API refused a gzip-encoded request body
+	gzipRejected atomic.Bool
+	// userAgent and correlationId identify the provider run in every request
+	userAgent     string
+	correlationId string
 }
 
 // AuthStruct -
@@ -31,38 +54,30 @@ type AuthResponse struct {
 	Token    string
 }
 
-// NewClient -
+// NewClient - Creates a client of the Qwilt APIs, see New for the other settings
 func NewClient(envType,
 	username,
 	password,
 	xApiToken string) (*Client, error) {
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 40 * time.Second},
-		envType:    "",
-		Auth: AuthStruct{
-			Username: username,
-			Password: password,
-		},
-	}
-
-	c.envType = envType
-	c.XApiToken = xApiToken
-
-	c.endpointBuilder = NewEndpointBuilder(c.envType)
-	c.authEndpoint = c.endpointBuilder.Build("login")
-
-	// Attempt to sign in using username/password if no token specified
-	// and auth endpoint has been provided.
-	// TODO: Can we defer this to be lazily sign in?
-	if c.authEndpoint != "" && xApiToken == "" {
-		ar, err := c.SignIn()
-		if err != nil {
-			return nil, err
-		}
-		c.Token = ar.Token
+	return New(WithEnvType(envType), WithCredentials(username, password), WithAPIKey(xApiToken))
+}
+
+// SetEndpoint - Overrides the base URL of one of the Qwilt services (media-sites, cert-manager, device-ip, login).
+// Must be called before creating the service clients.
+func (c *Client) SetEndpoint(service string, baseUrl string) {
+	c.endpointBuilder.SetOverride(service, baseUrl)
+	if service == api.LOGIN_HOSTNAME {
+		c.authEndpoint = c.endpointBuilder.Build(api.LOGIN_HOSTNAME)
 	}
+}
 
-	return &c, nil
+// SetRateLimit - Limits the rate of API requests shared by all service clients. 0 disables the limit.
+func (c *Client) SetRateLimit(requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = newRateLimiter(requestsPerSecond)
 }
 
 // doAuthRequest - Authenticates against the login API to get a token
@@ -71,13 +86,7 @@ func (c *Client) doAuthRequest(req *http.Request) (*string, error) {
 	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
 	req.Header.Add("Authorization", basicAuth)
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	_, err = io.ReadAll(res.Body)
+	res, _, err := c.send(req, false)
 	if err != nil {
 		return nil, err
 	}
@@ -106,33 +115,181 @@ func (c *Client) doAuthRequest(req *http.Request) (*string, error) {
 	return &token, err
 }
 
-// doRequest - Performs a typical request against the API
+// doRequest - Performs a typical request against the API and returns the response body
 func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	_, body, err := c.do(req, false)
+	return body, err
+}
+
+// doStreamRequest - Performs a request against the API and returns the unread response body,
+// to be closed by the caller. Used for large responses that are decoded while downloaded.
+func (c *Client) doStreamRequest(req *http.Request) (io.ReadCloser, error) {
+	res, _, err := c.do(req, true)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// do - Performs a request against the API.
+// Transient failures are retried with exponential backoff, up to MaxRetries times.
+// When a session token is rejected, the client signs in again and replays the request once.
+// When stream is set, the body of a successful response is left open for the caller.
+func (c *Client) do(req *http.Request, stream bool) (*http.Response, []byte, error) {
+	token, err := c.authorize(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// A mutation invalidates the cached lookups of the same object, also once it is done,
+	// so a lookup that raced with it is not served afterwards
+	c.invalidateCache(req)
+	defer c.invalidateCache(req)
+
+	restorePlainBody, err := c.compressBody(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		res, body, err := c.send(req, stream)
+
+		if err == nil && res.StatusCode == http.StatusUnsupportedMediaType && restorePlainBody != nil {
+			// The API does not accept gzip-encoded bodies, send this one and the next ones plain
+			c.gzipRejected.Store(true)
+			tflog.Debug(req.Context(), "Qwilt API rejected a gzip-encoded request body, sending it uncompressed", map[string]any{"method": req.Method, "url": req.URL.String()})
+			if err := restorePlainBody(); err != nil {
+				return nil, nil, err
+			}
+			restorePlainBody = nil
+			attempt--
+			continue
+		}
+
+		if err == nil && res.StatusCode == http.StatusUnauthorized && c.XApiToken == "" && !reauthenticated {
+			reauthenticated = true
+			tflog.Debug(req.Context(), "Qwilt session token rejected, signing in again", map[string]any{"method": req.Method, "url": req.URL.String()})
+			if _, err := c.refreshToken(req.Context(), token); err != nil {
+				return nil, nil, err
+			}
+			if token, err = c.authorize(req); err != nil {
+				return nil, nil, err
+			}
+			if err := rewindBody(req); err != nil {
+				return nil, nil, err
+			}
+			// The replay does not count as a retry
+			attempt--
+			continue
+		}
+
+		if attempt >= c.MaxRetries || !isRetryable(req, res, err) {
+			if err != nil {
+				return nil, nil, err
+			}
+			return res, body, checkResponse(res, body)
+		}
+
+		wait := retryWait(attempt, res, c.RetryMaxWait)
+		fields := map[string]any{"method": req.Method, "url": req.URL.String(), "attempt": attempt + 1, "wait": wait.String()}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = res.StatusCode
+		}
+		tflog.Debug(req.Context(), "Retrying Qwilt API request after transient failure", fields)
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, nil, err
+		}
+		if err := rewindBody(req); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// authorize - Sets the Authorization header and returns the session token used, if any
+func (c *Client) authorize(req *http.Request) (string, error) {
 	if c.XApiToken != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("X-API-KEY %s", c.XApiToken))
-	} else {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+		return "", nil
 	}
-	req.Header.Set("Content-Type", "application/json")
+
+	token, err := c.token(req.Context())
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return token, nil
+}
+
+// rewindBody - Resets the request body so the request can be sent again
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// send - Performs a single attempt of a request and reads the whole response body.
+// When stream is set, the body of a successful response is left unread instead.
+func (c *Client) send(req *http.Request, stream bool) (*http.Response, []byte, error) {
+	if c.limiter != nil {
+		waited, err := c.limiter.wait(req.Context())
+		if waited > 0 {
+			tflog.Debug(req.Context(), "Throttled Qwilt API request by client-side rate limit", map[string]any{"method": req.Method, "url": req.URL.String(), "wait": waited.String()})
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.correlationId != "" {
+		req.Header.Set(CORRELATION_ID_HEADER, c.correlationId)
+	}
+
+	logCtx := c.httpLogContext(req.Context())
+	logRequest(logCtx, req)
+	start := time.Now()
 
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		logResponse(logCtx, req, nil, nil, time.Since(start), err)
+		return nil, nil, err
+	}
+	if stream && isSuccess(res) {
+		logResponse(logCtx, req, res, nil, time.Since(start), nil)
+		return res, nil, nil
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
+	logResponse(logCtx, req, res, body, time.Since(start), err)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		if res.StatusCode == 401 {
-			err = fmt.Errorf("401 Unauthorized. please re-athenticate - status: %d", res.StatusCode)
-		} else {
-			err = fmt.Errorf("API command failed - status: %d, body: %s", res.StatusCode, body)
-		}
+	return res, body, nil
+}
+
+// checkResponse - Converts a non-successful response into an *APIError
+func checkResponse(res *http.Response, body []byte) error {
+	if !isSuccess(res) {
+		return newAPIError(res, body)
 	}
+	return nil
+}
 
-	return body, err
+func isSuccess(res *http.Response) bool {
+	return res.StatusCode == http.StatusOK || res.StatusCode == http.StatusCreated
 }
@@ -9,6 +9,7 @@
 package client_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
@@ -33,7 +34,7 @@ var version string = "dev"
 // https://goreleaser.com/cookbooks/using-main.version/
 
 func getSites(t *testing.T, client *cdnclient.SiteClientFacade, includeActiveLastPub bool) {
-	sites, err := client.GetSites(includeActiveLastPub, false)
+	sites, err := client.GetSites(context.Background(), includeActiveLastPub, false)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -50,7 +51,7 @@ func getSites(t *testing.T, client *cdnclient.SiteClientFacade, includeActiveLas
 }
 
 func getSiteIdByName(client *cdnclient.SiteClientFacade, name string) (string, error) {
-	sites, err := client.GetSites(false, false)
+	sites, err := client.GetSites(context.Background(), false, false)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -65,7 +66,7 @@ func getSiteIdByName(client *cdnclient.SiteClientFacade, name string) (string, e
 }
 
 func getSite(client *cdnclient.SiteClientFacade, siteId string, includeActiveLastPub bool) {
-	siteDetails, err := client.GetSite(siteId, cdnclient.TARGET_GA, includeActiveLastPub, false)
+	siteDetails, err := client.GetSite(context.Background(), siteId, cdnclient.TARGET_GA, includeActiveLastPub, false)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -83,7 +84,7 @@ func createSite(client *cdnclient.SiteClientFacade, siteName string, routingMeth
 	newSite.RoutingMethod = routingMethod
 
 	// Create new site
-	site, err := client.CreateSite(newSite)
+	site, err := client.CreateSite(context.Background(), newSite)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -94,7 +95,7 @@ func updateSite(client *cdnclient.SiteClientFacade, siteId string, siteName stri
 	var siteDetails api.SiteUpdateRequest
 	siteDetails.SiteName = siteName
 
-	siteResults, err := client.UpdateSite(siteId, siteDetails)
+	siteResults, err := client.UpdateSite(context.Background(), siteId, siteDetails)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -102,7 +103,7 @@ func updateSite(client *cdnclient.SiteClientFacade, siteId string, siteName stri
 }
 
 func deleteSite(client *cdnclient.SiteClientFacade, siteId string, name string) {
-	err := client.DeleteSite(siteId)
+	err := client.DeleteSite(context.Background(), siteId)
 	if err != nil {
 		log.Fatal(err.Error())
 	} else {
@@ -111,7 +112,7 @@ func deleteSite(client *cdnclient.SiteClientFacade, siteId string, name string)
 }
 
 func getSiteConfigs(client *cdnclient.SiteClientFacade, siteId string, truncateHostIndex bool) {
-	siteConfigs, err := client.GetSiteConfigs(siteId, truncateHostIndex)
+	siteConfigs, err := client.GetSiteConfigs(context.Background(), siteId, truncateHostIndex)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -123,7 +124,7 @@ func getSiteConfigs(client *cdnclient.SiteClientFacade, siteId string, truncateH
 }
 
 func getSiteConfig(client *cdnclient.SiteClientFacade, siteId string, revisionId string) {
-	siteConfigVersion, err := client.GetSiteConfig(siteId, revisionId, false)
+	siteConfigVersion, err := client.GetSiteConfig(context.Background(), siteId, revisionId, false)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -136,7 +137,7 @@ func createSiteConfig(client *cdnclient.SiteClientFacade, siteId string, hostInd
 	newSiteConfig.ChangeDescription = changeDescription
 	newSiteConfig.HostIndex = hostIndex
 
-	siteConfigResponse, err := client.CreateSiteConfig(siteId, newSiteConfig)
+	siteConfigResponse, err := client.CreateSiteConfig(context.Background(), siteId, newSiteConfig)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -144,7 +145,7 @@ func createSiteConfig(client *cdnclient.SiteClientFacade, siteId string, hostInd
 }
 
 func findLatestPubOp(client *cdnclient.SiteClientFacade, siteId string, revisionId string) {
-	pubOp, err := client.FindLatestPubOp(siteId, revisionId)
+	pubOp, err := client.FindLatestPubOp(context.Background(), siteId, revisionId)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -156,7 +157,7 @@ func findLatestPubOp(client *cdnclient.SiteClientFacade, siteId string, revision
 }
 
 func getPubOp(client *cdnclient.SiteClientFacade, siteId string, publishId string) {
-	pubOp, err := client.GetPubOp(siteId, publishId)
+	pubOp, err := client.GetPubOp(context.Background(), siteId, publishId)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -164,7 +165,7 @@ func getPubOp(client *cdnclient.SiteClientFacade, siteId string, publishId strin
 }
 
 func getPubOps(client *cdnclient.SiteClientFacade, siteId string, isActive bool, publishState string) {
-	pubOps, err := client.GetPubOps(siteId, isActive, publishState)
+	pubOps, err := client.GetPubOps(context.Background(), siteId, isActive, publishState)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -174,7 +175,7 @@ func getPubOps(client *cdnclient.SiteClientFacade, siteId string, isActive bool,
 }
 
 func getPubStatus(client *cdnclient.SiteClientFacade, siteId string) {
-	currentStatus, transitionStatus, err := client.GetSitePubStatus(siteId)
+	currentStatus, transitionStatus, err := client.GetSitePubStatus(context.Background(), siteId)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -183,7 +184,7 @@ func getPubStatus(client *cdnclient.SiteClientFacade, siteId string) {
 
 func publish(client *cdnclient.SiteClientFacade, siteId string, revisionId string) {
 	target := cdnclient.TARGET_GA
-	pubOp, err := client.Publish(siteId, revisionId, target)
+	pubOp, err := client.Publish(context.Background(), siteId, revisionId, target)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -192,7 +193,7 @@ func publish(client *cdnclient.SiteClientFacade, siteId string, revisionId strin
 
 func unpublish(client *cdnclient.SiteClientFacade, siteId string) {
 	target := cdnclient.TARGET_GA
-	pubOp, err := client.Unpublish(siteId, target)
+	pubOp, err := client.Unpublish(context.Background(), siteId, target)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -206,7 +207,11 @@ func auth(envType string, username string, password string, token string) (clien
 	}
 
 	if token == "" {
-		fmt.Printf("export QCDN_API_KEY='%s'\n", client.Token)
+		ar, err := client.SignIn(context.Background())
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Printf("export QCDN_API_KEY='%s'\n", ar.Token)
 	}
 
 	return client
@@ -0,0 +1,133 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// largeHostIndex - A host index large enough to be sent gzip-encoded
+func largeHostIndex() json.RawMessage {
+	hosts := []string{}
+	for i := 0; i < 2000; i++ {
+		hosts = append(hosts, fmt.Sprintf(`{"host":"www%d.example.com","pathList":[{"path":"/","handlerList":[]}]}`, i))
+	}
+	return json.RawMessage(`{"hosts":[` + strings.Join(hosts, ",") + `]}`)
+}
+
+type receivedRequest struct {
+	encoding string
+	body     string
+}
+
+// newCompressionTestServer - Records the requests, and rejects gzip-encoded bodies unless acceptGzip is set
+func newCompressionTestServer(t *testing.T, acceptGzip bool) (*httptest.Server, *[]receivedRequest) {
+	received := []receivedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		var body io.Reader = r.Body
+		if encoding == "gzip" {
+			if !acceptGzip {
+				received = append(received, receivedRequest{encoding: encoding})
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			zr, err := gzip.NewReader(r.Body)
+			assert.Nil(t, err)
+			body = zr
+		}
+		content, _ := io.ReadAll(body)
+		received = append(received, receivedRequest{encoding: encoding, body: string(content)})
+		w.Write([]byte(`{"siteId":"1","revisionId":"r1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func newGzipTestClient() *Client {
+	c := newRetryTestClient()
+	c.SetGzipRequests(true)
+	return c
+}
+
+func TestLargeRequestBodyIsGzipEncoded(t *testing.T) {
+	server, received := newCompressionTestServer(t, true)
+	c := &SiteConfigurationClient{Client: newGzipTestClient(), apiEndpoint: server.URL}
+
+	hostIndex := largeHostIndex()
+	_, err := c.CreateSiteConfig(context.Background(), "1", api.SiteConfigAddRequest{HostIndex: hostIndex, ChangeDescription: "large"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(*received))
+	assert.Equal(t, "gzip", (*received)[0].encoding)
+	sent := api.SiteConfigAddRequest{}
+	assert.Nil(t, json.Unmarshal([]byte((*received)[0].body), &sent))
+	assert.JSONEq(t, string(hostIndex), string(sent.HostIndex))
+
+	// Small bodies are not worth encoding
+	_, err = c.CreateSiteConfig(context.Background(), "1", api.SiteConfigAddRequest{HostIndex: json.RawMessage(`{"hosts":[]}`)})
+	assert.Nil(t, err)
+	assert.Equal(t, "", (*received)[1].encoding)
+}
+
+func TestGzipRequestsDisabledByDefault(t *testing.T) {
+	server, received := newCompressionTestServer(t, true)
+	c := &SiteConfigurationClient{Client: newRetryTestClient(), apiEndpoint: server.URL}
+
+	_, err := c.CreateSiteConfig(context.Background(), "1", api.SiteConfigAddRequest{HostIndex: largeHostIndex()})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(*received))
+	assert.Equal(t, "", (*received)[0].encoding)
+}
+
+func TestGzipRejectedFallsBackToPlainBody(t *testing.T) {
+	server, received := newCompressionTestServer(t, false)
+	c := &SiteConfigurationClient{Client: newGzipTestClient(), apiEndpoint: server.URL}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.CreateSiteConfig(context.Background(), "1", api.SiteConfigAddRequest{HostIndex: largeHostIndex()})
+		assert.Nil(t, err)
+	}
+
+	// The first body is sent again plain, the next ones are not encoded at all
+	assert.Equal(t, 3, len(*received))
+	assert.Equal(t, "gzip", (*received)[0].encoding)
+	assert.Equal(t, "", (*received)[1].encoding)
+	assert.Contains(t, (*received)[1].body, "www1999.example.com")
+	assert.Equal(t, "", (*received)[2].encoding)
+}
+
+func TestGzipResponseIsDecoded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept-Encoding"), "gzip")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		fmt.Fprintf(zw, `[{"siteId":"1","revisionId":"r1","hostIndex":%s}]`, largeHostIndex())
+		zw.Close()
+	}))
+	defer server.Close()
+
+	c := &SiteConfigurationClient{Client: newRetryTestClient(), apiEndpoint: server.URL}
+	versions, err := c.GetSiteConfigs(context.Background(), "1", false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(versions))
+	assert.Contains(t, string(versions[0].HostIndex), "www1999.example.com")
+}
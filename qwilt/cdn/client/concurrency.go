@@ -0,0 +1,125 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+)
+
+// getVersioned - Reads the current version of an object, bypassing the response cache.
+// Returns the ETag of the response, empty when the API does not send one.
+func (c *Client) getVersioned(ctx context.Context, url string, v any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, body, err := c.do(req, false)
+	if err != nil {
+		return "", err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return "", err
+	}
+	return res.Header.Get("ETag"), nil
+}
+
+// putIfMatch - Updates an object, on condition it still has the given ETag when one is set
+func (c *Client) putIfMatch(ctx context.Context, url string, etag string, payload any) ([]byte, error) {
+	req, err := newJSONRequest(ctx, "PUT", url, payload)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+	return c.doRequest(req)
+}
+
+// UpdateSiteIfUnmodified - Updates a site unless it changed between plan and apply, returning a *ModifiedError
+func (c *SiteClient) UpdateSiteIfUnmodified(ctx context.Context, siteId string, lastUpdateTimeMilli int64, site api.SiteUpdateRequest) (*api.Site, error) {
+	if siteId == "" {
+		return nil, fmt.Errorf("siteId is empty")
+	}
+
+	url := fmt.Sprintf("%s/api/v2/sites/%s", c.apiEndpoint, siteId)
+	current := api.Site{}
+	etag, err := c.getVersioned(ctx, url, &current)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsDeleted {
+		return nil, ErrSiteMarkedForDeletion
+	}
+	if int64(current.LastUpdateTimeMilli) != lastUpdateTimeMilli {
+		return nil, siteModifiedError(&current)
+	}
+
+	body, err := c.putIfMatch(ctx, url, etag, site)
+	if StatusCodeOf(err) == http.StatusPreconditionFailed {
+		// Modified between the read and the update
+		if _, readErr := c.getVersioned(ctx, url, &current); readErr != nil {
+			return nil, &ModifiedError{Object: "site " + siteId}
+		}
+		return nil, siteModifiedError(&current)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSite := api.Site{}
+	err = json.Unmarshal(body, &updatedSite)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedSite, nil
+}
+
+func siteModifiedError(site *api.Site) *ModifiedError {
+	return &ModifiedError{
+		Object:              "site " + site.SiteId,
+		LastUpdatedUser:     site.LastUpdatedUser,
+		LastUpdateTimeMilli: int64(site.LastUpdateTimeMilli),
+	}
+}
+
+// UpdateCertificateIfUnmodified - Updates a certificate unless its content changed between plan and apply, returning a *ModifiedError
+func (c *CertificatesClient) UpdateCertificateIfUnmodified(ctx context.Context, certId int64, lastRead api.Certificate, cert api.CertificateUpdateRequest) (*api.Certificate, error) {
+	current := api.Certificate{}
+	// The ETag of the detailed view does not apply to the updated one, so it is not used
+	_, err := c.getVersioned(ctx, fmt.Sprintf("%s/api/v2/certificates/%d?detailed=true", c.apiEndpoint, certId), &current)
+	if err != nil {
+		return nil, err
+	}
+	if certificateChanged(&current, &lastRead) {
+		return nil, &ModifiedError{Object: fmt.Sprintf("certificate %d", certId)}
+	}
+
+	return c.UpdateCertificate(ctx, certId, cert)
+}
+
+// certificateChanged - Reports whether the content of a certificate differs from the one last read.
+// Values missing from the last read are not compared.
+func certificateChanged(current *api.Certificate, lastRead *api.Certificate) bool {
+	differ := func(currentValue string, lastValue string) bool {
+		return lastValue != "" && strings.TrimSpace(currentValue) != strings.TrimSpace(lastValue)
+	}
+	return current.Description != lastRead.Description ||
+		differ(current.PkHash, lastRead.PkHash) ||
+		differ(current.Certificate, lastRead.Certificate) ||
+		differ(current.CertificateChain, lastRead.CertificateChain)
+}
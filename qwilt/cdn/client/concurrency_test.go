@@ -0,0 +1,83 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateSiteIfUnmodified(t *testing.T) {
+	ctx := context.Background()
+	server, facade := newFakeApiFacade(t, "", "", "fake-key")
+
+	site, err := facade.CreateSite(ctx, api.SiteCreateRequest{SiteName: "first"})
+	assert.Nil(t, err)
+
+	site, err = facade.UpdateSiteIfUnmodified(ctx, site.SiteId, int64(site.LastUpdateTimeMilli), api.SiteUpdateRequest{SiteName: "second"})
+	assert.Nil(t, err)
+	assert.Equal(t, "second", site.SiteName)
+
+	server.ModifySite(site.SiteId, "portal", "someone@example.com")
+	_, err = facade.UpdateSiteIfUnmodified(ctx, site.SiteId, int64(site.LastUpdateTimeMilli), api.SiteUpdateRequest{SiteName: "third"})
+	assert.True(t, IsModified(err))
+	assert.ErrorContains(t, err, "site "+site.SiteId+" was modified outside Terraform by someone@example.com at ")
+
+	current, err := facade.GetSite(ctx, site.SiteId, "", false, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "portal", current.SiteName)
+}
+
+func TestUpdateSiteIfUnmodifiedSendsIfMatch(t *testing.T) {
+	var ifMatch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"siteId":"1","lastUpdateTimeMilli":100,"lastUpdatedUser":"someone@example.com"}`))
+			return
+		}
+		ifMatch = r.Header.Get("If-Match")
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer server.Close()
+
+	c := &SiteClient{Client: newRetryTestClient(), apiEndpoint: server.URL}
+	_, err := c.UpdateSiteIfUnmodified(context.Background(), "1", 100, api.SiteUpdateRequest{SiteName: "new"})
+	assert.Equal(t, `"v1"`, ifMatch)
+	assert.True(t, IsModified(err))
+	assert.ErrorContains(t, err, "by someone@example.com")
+}
+
+func TestUpdateCertificateIfUnmodified(t *testing.T) {
+	ctx := context.Background()
+	server, facade := newFakeApiFacade(t, "", "", "fake-key")
+
+	cert, err := facade.CreateCertificate(ctx, api.CertificateCreateRequest{
+		Certificate:      "cert",
+		CertificateChain: "chain",
+		PrivateKey:       "key",
+		Description:      "first",
+	})
+	assert.Nil(t, err)
+
+	update := api.CertificateUpdateRequest{Certificate: "cert", CertificateChain: "chain", PrivateKey: "key", Description: "second"}
+	_, err = facade.UpdateCertificateIfUnmodified(ctx, cert.CertId, *cert, update)
+	assert.Nil(t, err)
+
+	server.ModifyCertificate(cert.CertId, "portal")
+	lastRead := *cert
+	lastRead.Description = "second"
+	_, err = facade.UpdateCertificateIfUnmodified(ctx, cert.CertId, lastRead, update)
+	assert.True(t, IsModified(err))
+}
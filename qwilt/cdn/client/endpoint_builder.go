@@ -8,11 +8,15 @@
 
 package client
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type EndpointBuilder struct {
-	domain string
-	prefix string
+	domain    string
+	prefix    string
+	overrides map[string]string
 }
 
 func NewEndpointBuilder(envType string) EndpointBuilder {
@@ -26,7 +30,7 @@ func NewEndpointBuilder(envType string) EndpointBuilder {
 		b.prefix = "prestg-"
 	} else if envType == "dev" {
 		b.domain = "rnd.cqloud.com"
-		b.prefix = "kan11-" //change this and re-build to run locally on different env on development time
+		b.prefix = "kan11-" //default dev env. use the provider 'endpoints' block to point at a different env
 	} else if envType == "prod" {
 		b.domain = "cqloud.com"
 		b.prefix = ""
@@ -34,6 +38,22 @@ func NewEndpointBuilder(envType string) EndpointBuilder {
 	return b
 }
 
+// SetOverride - Uses baseUrl for the named service instead of the env_type based domain.
+// An empty baseUrl removes the override.
+func (e *EndpointBuilder) SetOverride(name string, baseUrl string) {
+	if baseUrl == "" {
+		delete(e.overrides, name)
+		return
+	}
+	if e.overrides == nil {
+		e.overrides = map[string]string{}
+	}
+	e.overrides[name] = strings.TrimRight(baseUrl, "/")
+}
+
 func (e EndpointBuilder) Build(name string) string {
+	if baseUrl, ok := e.overrides[name]; ok {
+		return baseUrl
+	}
 	return fmt.Sprintf("https://%s%s.%s", e.prefix, name, e.domain)
 }
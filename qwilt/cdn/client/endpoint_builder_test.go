@@ -0,0 +1,32 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointOverrides(t *testing.T) {
+	c, err := NewClient("prod", "", "", "key")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://media-sites.cqloud.com", c.endpointBuilder.Build(api.SITES_HOSTNAME))
+
+	c.SetEndpoint(api.SITES_HOSTNAME, "http://localhost:8080/")
+	c.SetEndpoint(api.LOGIN_HOSTNAME, "https://gateway.example.com/login-api")
+	assert.Equal(t, "http://localhost:8080", c.endpointBuilder.Build(api.SITES_HOSTNAME))
+	assert.Equal(t, "https://gateway.example.com/login-api", c.authEndpoint)
+	assert.Equal(t, "https://cert-manager.cqloud.com", c.endpointBuilder.Build(api.CERT_MANAGER_HOSTNAME))
+	assert.Equal(t, "http://localhost:8080", NewSiteClient(api.SITES_HOSTNAME, c).apiEndpoint)
+
+	c.SetEndpoint(api.SITES_HOSTNAME, "")
+	assert.Equal(t, "https://media-sites.cqloud.com", c.endpointBuilder.Build(api.SITES_HOSTNAME))
+}
@@ -0,0 +1,136 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+)
+
+// ErrSiteMarkedForDeletion - Returned when a site still exists but was deleted by its owner
+var ErrSiteMarkedForDeletion = errors.New("Site was found but marked for deletion")
+
+// requestIdHeaders - Response headers that may carry the request ID, in order of preference
+var requestIdHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
+
+// APIError - A non-successful response returned by one of the Qwilt APIs
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	RequestId  string
+	Body       []byte
+	// ErrorBody holds the parsed error payload, nil if the body is not a JSON error document
+	ErrorBody *api.ErrorResponse
+}
+
+func newAPIError(res *http.Response, body []byte) *APIError {
+	apiErr := APIError{
+		StatusCode: res.StatusCode,
+		Body:       body,
+	}
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.URL = res.Request.URL.String()
+	}
+	for _, header := range requestIdHeaders {
+		if value := res.Header.Get(header); value != "" {
+			apiErr.RequestId = value
+			break
+		}
+	}
+
+	errorBody := api.ErrorResponse{}
+	if err := json.Unmarshal(body, &errorBody); err == nil {
+		apiErr.ErrorBody = &errorBody
+	}
+	return &apiErr
+}
+
+func (e *APIError) Error() string {
+	var msg string
+	if e.StatusCode == http.StatusUnauthorized {
+		msg = fmt.Sprintf("401 Unauthorized. please re-athenticate - status: %d", e.StatusCode)
+	} else {
+		msg = fmt.Sprintf("API command failed - status: %d, body: %s", e.StatusCode, e.Body)
+	}
+	if e.RequestId != "" {
+		msg += fmt.Sprintf(", request id: %s", e.RequestId)
+	}
+	return msg
+}
+
+// Message - Returns the most descriptive message found in the error payload
+func (e *APIError) Message() string {
+	if e.ErrorBody != nil {
+		if e.ErrorBody.Message != "" {
+			return e.ErrorBody.Message
+		}
+		if e.ErrorBody.Error != "" {
+			return e.ErrorBody.Error
+		}
+	}
+	return string(e.Body)
+}
+
+// StatusCodeOf - Returns the HTTP status code of an API error, or 0 if err is not an API error
+func StatusCodeOf(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// ModifiedError - Returned by the conditional updates when the object was modified
+// by someone else between plan and apply
+type ModifiedError struct {
+	// Object describes the object, such as "site 6489ab..."
+	Object string
+	// LastUpdatedUser and LastUpdateTimeMilli are empty when the API does not report them
+	LastUpdatedUser     string
+	LastUpdateTimeMilli int64
+}
+
+func (e *ModifiedError) Error() string {
+	msg := fmt.Sprintf("%s was modified outside Terraform", e.Object)
+	if e.LastUpdatedUser != "" {
+		msg += " by " + e.LastUpdatedUser
+	}
+	if e.LastUpdateTimeMilli > 0 {
+		msg += " at " + time.UnixMilli(e.LastUpdateTimeMilli).UTC().Format(time.RFC3339)
+	}
+	return msg
+}
+
+// IsModified - Reports whether a conditional update was refused as the object was modified by someone else
+func IsModified(err error) bool {
+	var modifiedErr *ModifiedError
+	return errors.As(err, &modifiedErr)
+}
+
+// IsNotFound - Reports whether the requested object does not exist (anymore)
+func IsNotFound(err error) bool {
+	return StatusCodeOf(err) == http.StatusNotFound || errors.Is(err, ErrSiteMarkedForDeletion)
+}
+
+// IsConflict - Reports whether the request conflicts with the current state of the object
+func IsConflict(err error) bool {
+	return StatusCodeOf(err) == http.StatusConflict
+}
+
+// IsUnauthorized - Reports whether the request was rejected due to missing or expired credentials
+func IsUnauthorized(err error) bool {
+	return StatusCodeOf(err) == http.StatusUnauthorized
+}
@@ -0,0 +1,46 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoRequestReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":404,"error":"Not Found","message":"site 123 does not exist"}`))
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", server.URL+"/api/v2/sites/123", nil)
+	_, err := newRetryTestClient().doRequest(req)
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "req-1", apiErr.RequestId)
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Equal(t, "site 123 does not exist", apiErr.Message())
+	assert.True(t, IsNotFound(err))
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", err)))
+	assert.False(t, IsConflict(err))
+}
+
+func TestIsNotFoundForDeletedSite(t *testing.T) {
+	assert.True(t, IsNotFound(ErrSiteMarkedForDeletion))
+	assert.False(t, IsNotFound(fmt.Errorf("siteId is empty")))
+}
@@ -0,0 +1,156 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/internal/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeApiFacade(t *testing.T, username, password, xApiToken string) (*fakeapi.Server, *SiteClientFacade) {
+	server := fakeapi.NewServer()
+	t.Cleanup(server.Close)
+
+	c, err := NewClient("dev", username, password, xApiToken)
+	assert.Nil(t, err)
+	for service, baseUrl := range server.Endpoints() {
+		c.SetEndpoint(service, baseUrl)
+	}
+	return server, NewSiteFacadeClient(api.SITES_HOSTNAME, c)
+}
+
+func TestFakeApiPublishLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, facade := newFakeApiFacade(t, "", "", "fake-key")
+
+	site, err := facade.CreateSite(ctx, api.SiteCreateRequest{SiteName: "fake site"})
+	assert.Nil(t, err)
+	assert.Equal(t, fakeapi.ORG_ID, site.OwnerOrgId)
+
+	config, err := facade.CreateSiteConfig(ctx, site.SiteId, api.SiteConfigAddRequest{
+		HostIndex:         json.RawMessage(`{"hosts":[]}`),
+		ChangeDescription: "first",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, config.RevisionNum)
+
+	pubOp, err := facade.Publish(ctx, site.SiteId, config.RevisionId, TARGET_GA)
+	assert.Nil(t, err)
+	assert.Equal(t, ACCEPTANCE_STATUS_PENDING, pubOp.PublishAcceptanceStatus)
+
+	// A second publish conflicts with the one in progress
+	_, err = facade.Publish(ctx, site.SiteId, config.RevisionId, TARGET_GA)
+	assert.True(t, IsConflict(err))
+
+	pubOp, err = facade.GetAndWaitForPubOpAcceptance(ctx, site.SiteId, pubOp.PublishId, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "Accepted", pubOp.PublishAcceptanceStatus)
+	assert.Equal(t, "InProgress", pubOp.PublishStatus)
+
+	pubOp, err = facade.GetPubOp(ctx, site.SiteId, pubOp.PublishId)
+	assert.Nil(t, err)
+	assert.Equal(t, "Success", pubOp.PublishStatus)
+	assert.True(t, pubOp.IsActive)
+
+	site, err = facade.GetSite(ctx, site.SiteId, TARGET_GA, true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, pubOp.PublishId, site.ActiveAndLastPublishingOperation.Active.PublishId)
+
+	err = facade.DeleteSite(ctx, site.SiteId)
+	assert.Nil(t, err)
+	_, err = facade.GetSite(ctx, site.SiteId, "", false, false)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestFakeApiRejectedRevision(t *testing.T) {
+	ctx := context.Background()
+	server, facade := newFakeApiFacade(t, "", "", "fake-key")
+
+	site, _ := facade.CreateSite(ctx, api.SiteCreateRequest{SiteName: "fake site"})
+	config, _ := facade.CreateSiteConfig(ctx, site.SiteId, api.SiteConfigAddRequest{HostIndex: json.RawMessage(`{}`)})
+	server.RejectRevision(config.RevisionId, json.RawMessage(`[{"message":"invalid origin"}]`))
+
+	pubOp, err := facade.Publish(ctx, site.SiteId, config.RevisionId, TARGET_GA)
+	assert.Nil(t, err)
+	pubOp, err = facade.GetAndWaitForPubOpAcceptance(ctx, site.SiteId, pubOp.PublishId, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, ACCEPTANCE_STATUS_INVALID, pubOp.PublishAcceptanceStatus)
+	assert.JSONEq(t, `[{"message":"invalid origin"}]`, string(pubOp.ValidatorsErrDetails))
+}
+
+func TestFakeApiWaitForCompletion(t *testing.T) {
+	ctx := context.Background()
+	server, facade := newFakeApiFacade(t, "", "", "fake-key")
+	facade.PublishOpsClient.PollInterval = time.Millisecond
+
+	site, _ := facade.CreateSite(ctx, api.SiteCreateRequest{SiteName: "fake site"})
+	config, _ := facade.CreateSiteConfig(ctx, site.SiteId, api.SiteConfigAddRequest{HostIndex: json.RawMessage(`{}`)})
+
+	pubOp, err := facade.Publish(ctx, site.SiteId, config.RevisionId, TARGET_GA)
+	assert.Nil(t, err)
+	pubOp, err = facade.GetAndWaitForPubOpCompletion(ctx, site.SiteId, pubOp.PublishId, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, PUBLISH_STATUS_SUCCESS, pubOp.PublishStatus)
+	assert.Equal(t, []string{"Validation passed", "Deployed to the CDN"}, pubOp.StatusLine)
+
+	rejected, _ := facade.CreateSiteConfig(ctx, site.SiteId, api.SiteConfigAddRequest{HostIndex: json.RawMessage(`{}`)})
+	server.RejectRevision(rejected.RevisionId, json.RawMessage(`[{"message":"invalid origin"}]`))
+	pubOp, err = facade.Publish(ctx, site.SiteId, rejected.RevisionId, TARGET_GA)
+	assert.Nil(t, err)
+	pubOp, err = facade.GetAndWaitForPubOpCompletion(ctx, site.SiteId, pubOp.PublishId, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, PUBLISH_STATUS_FAILED, pubOp.PublishStatus)
+}
+
+func TestFakeApiCertificates(t *testing.T) {
+	ctx := context.Background()
+	_, facade := newFakeApiFacade(t, "user", "pass", "")
+
+	template, err := facade.CreateCertificateTemplate(ctx, api.CertificateTemplateCreateRequest{
+		CommonName:                     "www.example.com",
+		SANs:                           []string{"example.com"},
+		AutoManagedCertificateTemplate: true,
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, template.LastCertificateID)
+
+	domains, err := facade.GetChallengeDelegationDomainsListFromCertificateTemplateId(ctx, template.CertificateTemplateID)
+	assert.Nil(t, err)
+	assert.Len(t, domains.pairs, 2)
+
+	site, _ := facade.CreateSite(ctx, api.SiteCreateRequest{SiteName: "fake site"})
+	certId := strconv.FormatInt(*template.LastCertificateID, 10)
+	_, err = facade.LinkSiteCertificate(ctx, site.SiteId, certId)
+	assert.Nil(t, err)
+
+	err = facade.DeleteCertificate(ctx, *template.LastCertificateID)
+	assert.True(t, IsConflict(err))
+
+	assert.Nil(t, facade.UnLinkSiteCertificate(ctx, site.SiteId, certId))
+	assert.Nil(t, facade.DeleteCertificate(ctx, *template.LastCertificateID))
+	assert.Nil(t, facade.DeleteCertificateTemplate(ctx, template.CertificateTemplateID))
+
+	_, err = facade.GetCertificateTemplate(ctx, template.CertificateTemplateID)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestFakeApiOriginAllowList(t *testing.T) {
+	_, facade := newFakeApiFacade(t, "", "", "fake-key")
+
+	ips, err := NewDeviceIpsClient(facade.SiteClient.Client).GetOriginAllowList(context.Background())
+	assert.Nil(t, err)
+	assert.NotEmpty(t, ips.IpData)
+}
@@ -0,0 +1,93 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+)
+
+// SitesAPI - The sites of the Sites API, implemented by SiteClient
+type SitesAPI interface {
+	GetSites(ctx context.Context, includeActiveLastPub bool, includeDeletedSites bool) ([]api.Site, error)
+	ListSites(ctx context.Context, includeActiveLastPub bool, includeDeletedSites bool) (*ListIterator[api.Site], error)
+	GetSite(ctx context.Context, siteId string, target string, includeActiveLastPub bool, includeDeletedSites bool) (*api.Site, error)
+	GetSiteUncached(ctx context.Context, siteId string, target string, includeActiveLastPub bool, includeDeletedSites bool) (*api.Site, error)
+	CreateSite(ctx context.Context, site api.SiteCreateRequest) (*api.Site, error)
+	UpdateSite(ctx context.Context, siteId string, site api.SiteUpdateRequest) (*api.Site, error)
+	UpdateSiteIfUnmodified(ctx context.Context, siteId string, lastUpdateTimeMilli int64, site api.SiteUpdateRequest) (*api.Site, error)
+	DeleteSite(ctx context.Context, siteId string) error
+}
+
+// SiteConfigurationsAPI - The configuration revisions of the sites, implemented by SiteConfigurationClient
+type SiteConfigurationsAPI interface {
+	GetSiteConfigs(ctx context.Context, siteId string, truncateHostIndex bool) ([]api.SiteConfigVersion, error)
+	GetSiteConfig(ctx context.Context, siteId string, revisionId string, truncateHostIndex bool) (*api.SiteConfigVersion, error)
+	CreateSiteConfig(ctx context.Context, siteId string, siteConfigVersion api.SiteConfigAddRequest) (*api.SiteConfigVersion, error)
+}
+
+// PublishingAPI - The publishing operations of the sites, implemented by PublishOpsClient
+type PublishingAPI interface {
+	FindLatestPubOp(ctx context.Context, siteId string, revisionId string) (*api.PubOp, error)
+	GetSitePubStatus(ctx context.Context, siteId string) (string, string, error)
+	GetPubOps(ctx context.Context, siteId string, isActive bool, publishState string) ([]api.PubOp, error)
+	GetPubOp(ctx context.Context, siteId string, publishId string) (*api.PubOp, error)
+	GetAndWaitForPubOpAcceptance(ctx context.Context, siteId string, publishId string, timeout time.Duration) (*api.PubOp, error)
+	GetAndWaitForPubOpCompletion(ctx context.Context, siteId string, publishId string, timeout time.Duration) (*api.PubOp, error)
+	Publish(ctx context.Context, siteId string, revisionId string, target string) (*api.PubOp, error)
+	Unpublish(ctx context.Context, siteId string, target string) (*api.PubOp, error)
+	Republish(ctx context.Context, siteId string, target string) (*api.PubOp, error)
+	Cancel(ctx context.Context, siteId string, publishId string) error
+}
+
+// SiteCertificatesAPI - The certificates linked to the sites, implemented by SiteCertificatesClient
+type SiteCertificatesAPI interface {
+	GetSiteCertificates(ctx context.Context, siteId string, revisionId string) ([]api.SiteCertificateResponse, error)
+	LinkSiteCertificate(ctx context.Context, siteId string, certId string) (*api.SiteCertificateResponse, error)
+	UnLinkSiteCertificate(ctx context.Context, siteId string, certId string) error
+}
+
+// CertificatesAPI - The certificates of the Certificate Manager API, implemented by CertificatesClient
+type CertificatesAPI interface {
+	GetCertificates(ctx context.Context, detailed bool) ([]api.Certificate, error)
+	ListCertificates(ctx context.Context, detailed bool) (*ListIterator[api.Certificate], error)
+	GetCertificate(ctx context.Context, certId int64, detailed bool) (*api.Certificate, error)
+	CreateCertificate(ctx context.Context, cert api.CertificateCreateRequest) (*api.Certificate, error)
+	UpdateCertificate(ctx context.Context, certId int64, cert api.CertificateUpdateRequest) (*api.Certificate, error)
+	UpdateCertificateIfUnmodified(ctx context.Context, certId int64, lastRead api.Certificate, cert api.CertificateUpdateRequest) (*api.Certificate, error)
+	DeleteCertificate(ctx context.Context, certId int64) error
+}
+
+// CertificateTemplatesAPI - The certificate templates of the Certificate Manager API, and their
+// signing requests, implemented by CertificateTemplateClient
+type CertificateTemplatesAPI interface {
+	GetCertificateTemplates(ctx context.Context) ([]api.CertificateTemplate, error)
+	ListCertificateTemplates(ctx context.Context) (*ListIterator[api.CertificateTemplate], error)
+	GetCertificateTemplate(ctx context.Context, id int64) (*api.CertificateTemplate, error)
+	CreateCertificateTemplate(ctx context.Context, cert api.CertificateTemplateCreateRequest) (*api.CertificateTemplate, error)
+	DeleteCertificateTemplate(ctx context.Context, id int64) error
+	GetChallengeDelegationDomainsListFromCertificateTemplateId(ctx context.Context, id int64) (*ChallengeDelegationMap, error)
+}
+
+// OriginAllowListAPI - The IP addresses the origins must allow, implemented by DeviceIpsClient
+type OriginAllowListAPI interface {
+	GetOriginAllowList(ctx context.Context) (*api.DeviceIpsModel, error)
+}
+
+var (
+	_ SitesAPI                = (*SiteClient)(nil)
+	_ SiteConfigurationsAPI   = (*SiteConfigurationClient)(nil)
+	_ PublishingAPI           = (*PublishOpsClient)(nil)
+	_ SiteCertificatesAPI     = (*SiteCertificatesClient)(nil)
+	_ CertificatesAPI         = (*CertificatesClient)(nil)
+	_ CertificateTemplatesAPI = (*CertificateTemplateClient)(nil)
+	_ OriginAllowListAPI      = (*DeviceIpsClient)(nil)
+)
@@ -9,6 +9,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
@@ -23,16 +24,16 @@ type DeviceIpsClient struct {
 func NewDeviceIpsClient(client *Client) *DeviceIpsClient {
 	c := DeviceIpsClient{
 		Client:      client,
-		apiEndpoint: client.endpointBuilder.Build("device-ip"),
+		apiEndpoint: client.endpointBuilder.Build(api.DEVICE_IP_HOSTNAME),
 	}
 	return &c
 }
 
 // GetCertificates - Returns list of certificates
-func (c *DeviceIpsClient) GetOriginAllowList() (*api.DeviceIpsModel, error) {
+func (c *DeviceIpsClient) GetOriginAllowList(ctx context.Context) (*api.DeviceIpsModel, error) {
 
 	URL := fmt.Sprintf("%s/api/1.0/network/device-ip", c.apiEndpoint)
-	req, err := http.NewRequest("GET", URL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", URL, nil)
 	if err != nil {
 		return nil, err
 	}
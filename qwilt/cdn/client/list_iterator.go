@@ -0,0 +1,136 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ListIterator - Decodes the items of a JSON array response one at a time, while it is downloaded.
+// The list endpoints of the Qwilt APIs return the whole collection in one response, so the
+// iterator keeps memory bounded by a single item and lets callers stop reading early.
+//
+//	it, err := client.ListSites(ctx, true, false)
+//	...
+//	defer it.Close()
+//	for it.Next() {
+//		site := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ListIterator[T any] struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	started bool
+	done    bool
+	value   T
+	err     error
+	// skip drops items the caller did not ask for, e.g. deleted sites
+	skip func(T) bool
+	// items are iterated instead of a response body, see NewSliceIterator
+	items []T
+}
+
+func newListIterator[T any](body io.ReadCloser) *ListIterator[T] {
+	return &ListIterator[T]{
+		body:    body,
+		decoder: json.NewDecoder(body),
+	}
+}
+
+// NewSliceIterator - Returns an iterator over items already in memory, e.g. for mocks of the list methods
+func NewSliceIterator[T any](items []T) *ListIterator[T] {
+	return &ListIterator[T]{
+		body:  io.NopCloser(nil),
+		items: items,
+	}
+}
+
+// Next - Decodes the next item, returns false at the end of the list or on error
+func (it *ListIterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if it.decoder == nil {
+		for len(it.items) > 0 {
+			value := it.items[0]
+			it.items = it.items[1:]
+			if it.skip != nil && it.skip(value) {
+				continue
+			}
+			it.value = value
+			return true
+		}
+		return it.finish(nil)
+	}
+
+	if !it.started {
+		it.started = true
+		token, err := it.decoder.Token()
+		if err != nil {
+			return it.finish(fmt.Errorf("could not decode list response: %w", err))
+		}
+		if token == nil {
+			// A null list
+			return it.finish(nil)
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return it.finish(fmt.Errorf("could not decode list response: expected an array, got %v", token))
+		}
+	}
+
+	for it.decoder.More() {
+		var value T
+		if err := it.decoder.Decode(&value); err != nil {
+			return it.finish(fmt.Errorf("could not decode list item: %w", err))
+		}
+		if it.skip != nil && it.skip(value) {
+			continue
+		}
+		it.value = value
+		return true
+	}
+	return it.finish(nil)
+}
+
+// Value - Returns the item decoded by the last call to Next
+func (it *ListIterator[T]) Value() T {
+	return it.value
+}
+
+// Err - Returns the error that stopped the iteration, if any
+func (it *ListIterator[T]) Err() error {
+	return it.err
+}
+
+// Close - Releases the response, the rest of the list is not downloaded
+func (it *ListIterator[T]) Close() error {
+	it.done = true
+	return it.body.Close()
+}
+
+func (it *ListIterator[T]) finish(err error) bool {
+	it.err = err
+	it.Close()
+	return false
+}
+
+// collect - Reads all the remaining items of an iterator
+func collect[T any](it *ListIterator[T]) ([]T, error) {
+	defer it.Close()
+	items := []T{}
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,77 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListIteratorDecodesItems(t *testing.T) {
+	it := newListIterator[api.Site](io.NopCloser(strings.NewReader(
+		`[{"siteId":"a"},{"siteId":"b","IsDeleted":true},{"siteId":"c"}]`)))
+	it.skip = func(site api.Site) bool { return site.IsDeleted }
+
+	sites, err := collect(it)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sites))
+	assert.Equal(t, "a", sites[0].SiteId)
+	assert.Equal(t, "c", sites[1].SiteId)
+	assert.False(t, it.Next())
+}
+
+func TestListIteratorErrors(t *testing.T) {
+	sites, err := collect(newListIterator[api.Site](io.NopCloser(strings.NewReader(`null`))))
+	assert.Nil(t, err)
+	assert.Empty(t, sites)
+
+	_, err = collect(newListIterator[api.Site](io.NopCloser(strings.NewReader(`{"siteId":"a"}`))))
+	assert.ErrorContains(t, err, "expected an array")
+
+	_, err = collect(newListIterator[api.Site](io.NopCloser(strings.NewReader(`[{"siteId":"a"},{"siteId":`))))
+	assert.ErrorContains(t, err, "could not decode list item")
+}
+
+func TestListSitesStopsEarly(t *testing.T) {
+	// A large list, streamed in chunks
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("["))
+		for i := 0; i < 100000; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"siteId":"site-%d","siteName":"%s"}`, i, strings.Repeat("x", 100))
+		}
+		w.Write([]byte("]"))
+	}))
+	defer server.Close()
+
+	c := &SiteClient{Client: newRetryTestClient(), apiEndpoint: server.URL}
+	it, err := c.ListSites(context.Background(), false, false)
+	assert.Nil(t, err)
+
+	found := ""
+	for it.Next() {
+		if it.Value().SiteId == "site-3" {
+			found = it.Value().SiteId
+			break
+		}
+	}
+	assert.Nil(t, it.Close())
+	assert.Nil(t, it.Err())
+	assert.Equal(t, "site-3", found)
+}
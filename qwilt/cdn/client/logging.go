@@ -0,0 +1,139 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// HTTP_LOG_SUBSYSTEM - The tflog subsystem of the HTTP traffic with the Qwilt APIs.
+// Its level can be set separately with the TF_LOG_PROVIDER_QWILT_HTTP env variable.
+const HTTP_LOG_SUBSYSTEM = "qwilt_http"
+
+const HTTP_LOG_LEVEL_ENV = "TF_LOG_PROVIDER_QWILT_HTTP"
+
+// logLevelEnvs - The env variables setting the level of the HTTP logs, most specific first
+var logLevelEnvs = []string{HTTP_LOG_LEVEL_ENV, "TF_LOG_PROVIDER_QWILT", "TF_LOG_PROVIDER", "TF_LOG"}
+
+// maxLoggedBodySize - Bodies larger than this (e.g. big host indexes) are truncated in the logs
+const maxLoggedBodySize = 64 * 1024
+
+const redacted = "***"
+
+// sensitiveHeaders - Headers whose values are never logged
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"X-Api-Key":           true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// sensitiveBodyFields - JSON string fields whose values are never logged
+var sensitiveBodyFields = regexp.MustCompile(`("(?i:password|privateKey)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// httpLogContext - Returns a context with the HTTP logging subsystem, masking the client credentials
+func (c *Client) httpLogContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, HTTP_LOG_SUBSYSTEM, tflog.WithLevelFromEnv(HTTP_LOG_LEVEL_ENV))
+
+	secrets := []string{}
+	if c.XApiToken != "" {
+		secrets = append(secrets, c.XApiToken)
+	}
+	if c.Auth.Password != "" {
+		secrets = append(secrets, c.Auth.Password)
+	}
+	return tflog.SubsystemMaskAllFieldValuesStrings(ctx, HTTP_LOG_SUBSYSTEM, secrets...)
+}
+
+// httpTraceEnabled - Reports whether the TRACE logs of the HTTP subsystem are output. tflog cannot tell, so the
+// env variables are resolved the way Terraform does, and the acceptance tests log everything to TF_ACC_LOG_PATH.
+func httpTraceEnabled() bool {
+	if os.Getenv(HTTP_LOG_LEVEL_ENV) == "" && os.Getenv("TF_ACC_LOG_PATH") != "" {
+		return true
+	}
+	for _, env := range logLevelEnvs {
+		if level := strings.ToUpper(os.Getenv(env)); level != "" {
+			return level == "TRACE" || level == "JSON"
+		}
+	}
+	return false
+}
+
+// logRequest - Logs an outgoing request at TRACE level.
+// Bodies can be several MB, so nothing is built unless TRACE is enabled.
+func logRequest(ctx context.Context, req *http.Request) {
+	if !httpTraceEnabled() {
+		return
+	}
+	fields := map[string]any{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"headers": redactHeaders(req.Header),
+	}
+	if content, err := requestBody(req); err == nil && content != nil {
+		fields["body"] = redactBody(content)
+	}
+	tflog.SubsystemTrace(ctx, HTTP_LOG_SUBSYSTEM, "Sending Qwilt API request", fields)
+}
+
+// logResponse - Logs the outcome of a request at TRACE level
+func logResponse(ctx context.Context, req *http.Request, res *http.Response, body []byte, latency time.Duration, err error) {
+	if !httpTraceEnabled() {
+		return
+	}
+	fields := map[string]any{
+		"method":     req.Method,
+		"url":        req.URL.String(),
+		"latency_ms": latency.Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemTrace(ctx, HTTP_LOG_SUBSYSTEM, "Qwilt API request failed", fields)
+		return
+	}
+	fields["status"] = res.StatusCode
+	fields["headers"] = redactHeaders(res.Header)
+	if body != nil {
+		fields["body"] = redactBody(body)
+	} else {
+		fields["body"] = "(streamed)"
+	}
+	tflog.SubsystemTrace(ctx, HTTP_LOG_SUBSYSTEM, "Received Qwilt API response", fields)
+}
+
+// redactHeaders - Flattens the headers for logging, masking the credentials
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// redactBody - Returns the body for logging, masking passwords and private keys
+func redactBody(body []byte) string {
+	// Mask before truncating so a secret cut in half is still masked
+	content := sensitiveBodyFields.ReplaceAllString(string(body), `$1"`+redacted+`"`)
+	if len(content) > maxLoggedBodySize {
+		content = content[:maxLoggedBodySize] + "... (truncated)"
+	}
+	return content
+}
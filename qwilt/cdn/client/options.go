@@ -0,0 +1,197 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+)
+
+// Option - Configures a Client created by New
+type Option func(*options) error
+
+type options struct {
+	envType          string
+	username         string
+	password         string
+	apiKey           string
+	httpClient       *http.Client
+	endpoints        map[string]string
+	maxRetries       int
+	retryMaxWait     time.Duration
+	pollInterval     time.Duration
+	pollMaxInterval  time.Duration
+	rateLimit        float64
+	responseCacheTtl time.Duration
+	gzipRequests     bool
+	userAgent        string
+}
+
+// WithEnvType - Selects the Qwilt environment [prod,prestg,stage,dev]. Defaults to prod.
+func WithEnvType(envType string) Option {
+	return func(o *options) error {
+		if envType != "" {
+			o.envType = envType
+		}
+		return nil
+	}
+}
+
+// WithCredentials - Signs in with a username and password, on the first request
+func WithCredentials(username string, password string) Option {
+	return func(o *options) error {
+		o.username = username
+		o.password = password
+		return nil
+	}
+}
+
+// WithAPIKey - Authenticates with an API key, preferred over WithCredentials when both are set
+func WithAPIKey(apiKey string) Option {
+	return func(o *options) error {
+		o.apiKey = apiKey
+		return nil
+	}
+}
+
+// WithHTTPClient - Sends the requests through httpClient, see NewHTTPClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		if httpClient == nil {
+			return fmt.Errorf("http client is nil")
+		}
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithEndpoint - Overrides the base URL of one of the Qwilt services, see SetEndpoint
+func WithEndpoint(service string, baseUrl string) Option {
+	return func(o *options) error {
+		o.endpoints[service] = baseUrl
+		return nil
+	}
+}
+
+// WithRetries - Retries transient failures up to maxRetries times, waiting up to maxWait between attempts
+func WithRetries(maxRetries int, maxWait time.Duration) Option {
+	return func(o *options) error {
+		if maxRetries < 0 || maxWait < 0 {
+			return fmt.Errorf("invalid retries: max retries %d, max wait %s", maxRetries, maxWait)
+		}
+		o.maxRetries = maxRetries
+		o.retryMaxWait = maxWait
+		return nil
+	}
+}
+
+// WithPollInterval - Polls long running operations every interval at first, backing off up to maxInterval
+func WithPollInterval(interval time.Duration, maxInterval time.Duration) Option {
+	return func(o *options) error {
+		if interval <= 0 || maxInterval < interval {
+			return fmt.Errorf("invalid poll interval: interval %s, max interval %s", interval, maxInterval)
+		}
+		o.pollInterval = interval
+		o.pollMaxInterval = maxInterval
+		return nil
+	}
+}
+
+// WithRateLimit - Limits the rate of requests, see SetRateLimit
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(o *options) error {
+		if requestsPerSecond < 0 {
+			return fmt.Errorf("invalid rate limit %v", requestsPerSecond)
+		}
+		o.rateLimit = requestsPerSecond
+		return nil
+	}
+}
+
+// WithResponseCacheTTL - Caches object lookups, see SetResponseCacheTTL. Disabled by default.
+func WithResponseCacheTTL(ttl time.Duration) Option {
+	return func(o *options) error {
+		o.responseCacheTtl = ttl
+		return nil
+	}
+}
+
+// WithGzipRequests - Sends large request bodies gzip-encoded, see SetGzipRequests. Disabled by default.
+func WithGzipRequests(enabled bool) Option {
+	return func(o *options) error {
+		o.gzipRequests = enabled
+		return nil
+	}
+}
+
+// WithUserAgent - Sets the User-Agent sent with every request, such as "my-tool/1.0"
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) error {
+		o.userAgent = userAgent
+		return nil
+	}
+}
+
+// New - Creates a client of the Qwilt APIs.
+//
+//	c, err := client.New(client.WithAPIKey(key), client.WithRetries(5, time.Minute))
+//	...
+//	sites := client.NewSiteClient(api.SITES_HOSTNAME, c)
+func New(opts ...Option) (*Client, error) {
+	o := options{
+		envType:         "prod",
+		endpoints:       map[string]string{},
+		maxRetries:      DEFAULT_MAX_RETRIES,
+		retryMaxWait:    DEFAULT_RETRY_MAX_WAIT,
+		pollInterval:    DEFAULT_POLL_INTERVAL,
+		pollMaxInterval: DEFAULT_POLL_MAX_INTERVAL,
+		userAgent:       USER_AGENT_PRODUCT,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	c := Client{
+		HTTPClient: o.httpClient,
+		envType:    o.envType,
+		Auth: AuthStruct{
+			Username: o.username,
+			Password: o.password,
+		},
+		XApiToken:       o.apiKey,
+		MaxRetries:      o.maxRetries,
+		RetryMaxWait:    o.retryMaxWait,
+		PollInterval:    o.pollInterval,
+		PollMaxInterval: o.pollMaxInterval,
+		userAgent:       o.userAgent,
+		correlationId:   newCorrelationId(),
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: DEFAULT_REQUEST_TIMEOUT}
+	}
+
+	c.endpointBuilder = NewEndpointBuilder(c.envType)
+	c.authEndpoint = c.endpointBuilder.Build(api.LOGIN_HOSTNAME)
+	for service, baseUrl := range o.endpoints {
+		c.SetEndpoint(service, baseUrl)
+	}
+	c.SetRateLimit(o.rateLimit)
+	c.SetResponseCacheTTL(o.responseCacheTtl)
+	c.SetGzipRequests(o.gzipRequests)
+
+	// When no API key is specified, sign in using username/password is deferred
+	// until the first request, see token().
+
+	return &c, nil
+}
@@ -0,0 +1,79 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/internal/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	opts := []cdnclient.Option{
+		cdnclient.WithAPIKey("fake-key"),
+		cdnclient.WithRetries(1, time.Second),
+		cdnclient.WithUserAgent("my-tool/1.0"),
+	}
+	for service, baseUrl := range server.Endpoints() {
+		opts = append(opts, cdnclient.WithEndpoint(service, baseUrl))
+	}
+	c, err := cdnclient.New(opts...)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, c.MaxRetries)
+
+	var sites cdnclient.SitesAPI = cdnclient.NewSiteClient(api.SITES_HOSTNAME, c)
+	site, err := sites.CreateSite(context.Background(), api.SiteCreateRequest{SiteName: "sdk"})
+	assert.Nil(t, err)
+	assert.Equal(t, "sdk", site.SiteName)
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	_, err := cdnclient.New(cdnclient.WithRetries(-1, time.Second))
+	assert.NotNil(t, err)
+
+	_, err = cdnclient.New(cdnclient.WithRateLimit(-1))
+	assert.NotNil(t, err)
+
+	_, err = cdnclient.New(cdnclient.WithHTTPClient(nil))
+	assert.NotNil(t, err)
+
+	_, err = cdnclient.New(cdnclient.WithPollInterval(time.Minute, time.Second))
+	assert.NotNil(t, err)
+}
+
+// mockSites - A mock of SitesAPI, as users of the client would write in their tests
+type mockSites struct {
+	cdnclient.SitesAPI
+	sites []api.Site
+}
+
+func (m *mockSites) ListSites(ctx context.Context, includeActiveLastPub bool, includeDeletedSites bool) (*cdnclient.ListIterator[api.Site], error) {
+	return cdnclient.NewSliceIterator(m.sites), nil
+}
+
+func TestSitesAPIMock(t *testing.T) {
+	var sites cdnclient.SitesAPI = &mockSites{sites: []api.Site{{SiteId: "a"}, {SiteId: "b"}}}
+
+	it, err := sites.ListSites(context.Background(), false, false)
+	assert.Nil(t, err)
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Value().SiteId)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []string{"a", "b"}, ids)
+}
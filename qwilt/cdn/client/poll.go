@@ -0,0 +1,36 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import "time"
+
+const DEFAULT_POLL_INTERVAL = 3 * time.Second
+const DEFAULT_POLL_MAX_INTERVAL = 30 * time.Second
+
+// pollWait - Returns how long to wait before the next poll of a long running operation.
+// The wait starts at PollInterval and doubles on each poll, up to PollMaxInterval.
+func (c *Client) pollWait(poll int) time.Duration {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = DEFAULT_POLL_INTERVAL
+	}
+	maxInterval := c.PollMaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DEFAULT_POLL_MAX_INTERVAL
+	}
+	if maxInterval < interval {
+		return interval
+	}
+
+	wait := interval << min(poll, 30)
+	if wait <= 0 || wait > maxInterval {
+		wait = maxInterval
+	}
+	return wait
+}
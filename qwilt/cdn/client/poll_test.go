@@ -0,0 +1,78 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollWaitBacksOff(t *testing.T) {
+	c := &Client{PollInterval: time.Second, PollMaxInterval: 5 * time.Second}
+	assert.Equal(t, time.Second, c.pollWait(0))
+	assert.Equal(t, 2*time.Second, c.pollWait(1))
+	assert.Equal(t, 4*time.Second, c.pollWait(2))
+	assert.Equal(t, 5*time.Second, c.pollWait(3))
+	assert.Equal(t, 5*time.Second, c.pollWait(100))
+
+	// Unset values fall back to the defaults
+	c = &Client{}
+	assert.Equal(t, DEFAULT_POLL_INTERVAL, c.pollWait(0))
+	assert.Equal(t, DEFAULT_POLL_MAX_INTERVAL, c.pollWait(100))
+}
+
+func TestGetAndWaitForPubOpAcceptance(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.Write([]byte(`{"publishId":"p1","publishAcceptanceStatus":"Pending"}`))
+			return
+		}
+		w.Write([]byte(`{"publishId":"p1","publishAcceptanceStatus":"Accepted"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	client.PollInterval = time.Millisecond
+	client.PollMaxInterval = 2 * time.Millisecond
+	c := &PublishOpsClient{Client: client, apiEndpoint: server.URL}
+
+	pubOp, err := c.GetAndWaitForPubOpAcceptance(context.Background(), "1", "p1", time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "Accepted", pubOp.PublishAcceptanceStatus)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&polls))
+}
+
+func TestGetAndWaitForPubOpAcceptanceTimesOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"publishId":"p1","publishAcceptanceStatus":"Pending"}`))
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient()
+	client.PollInterval = 10 * time.Millisecond
+	c := &PublishOpsClient{Client: client, apiEndpoint: server.URL}
+
+	start := time.Now()
+	pubOp, err := c.GetAndWaitForPubOpAcceptance(context.Background(), "1", "p1", 50*time.Millisecond)
+	assert.ErrorContains(t, err, "TimedOut")
+	assert.Equal(t, "Pending", pubOp.PublishAcceptanceStatus)
+	assert.Less(t, time.Since(start), time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.GetAndWaitForPubOpAcceptance(ctx, "1", "p1", time.Minute)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -9,6 +9,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
@@ -16,6 +17,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 const TARGET_GA = "ga"
@@ -24,6 +27,10 @@ const ACCEPTANCE_STATUS_PENDING = "Pending"
 const ACCEPTANCE_STATUS_INVALID = "Invalid"
 const ACCEPTANCE_STATUS_DISMISSED = "Dismissed"
 const ACCEPTANCE_TIMEOUT = 180 * time.Second
+const PUBLISH_STATUS_IN_PROGRESS = "InProgress"
+const PUBLISH_STATUS_SUCCESS = "Success"
+const PUBLISH_STATUS_FAILED = "Failed"
+const PUBLISH_STATUS_ABORTED = "Aborted"
 
 type PublishOpsClient struct {
 	*Client
@@ -39,7 +46,7 @@ func NewPublishOpsClient(target string, client *Client) *PublishOpsClient {
 }
 
 // FindPubOp - Returns latest publishing operation for site
-func (c *PublishOpsClient) FindLatestPubOp(siteId string, revisionId string) (*api.PubOp, error) {
+func (c *PublishOpsClient) FindLatestPubOp(ctx context.Context, siteId string, revisionId string) (*api.PubOp, error) {
 	if siteId == "" || revisionId == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s revisionId=%s", siteId, revisionId)
 	}
@@ -47,7 +54,7 @@ func (c *PublishOpsClient) FindLatestPubOp(siteId string, revisionId string) (*a
 	pubOp := api.PubOp{}
 
 	// Get list of publish ops
-	pubOps, err := c.GetPubOps(siteId, false, "")
+	pubOps, err := c.GetPubOps(ctx, siteId, false, "")
 	if err != nil || len(pubOps) == 0 {
 		// No error, but no jobs found
 		return &pubOp, nil
@@ -75,8 +82,8 @@ func (c *PublishOpsClient) FindLatestPubOp(siteId string, revisionId string) (*a
 }
 
 // GetSitePubStatus - Get a site's publishing status
-func (c *PublishOpsClient) GetSitePubStatus(siteId string) (string, string, error) {
-	pubOps, err := c.GetPubOps(siteId, false, "")
+func (c *PublishOpsClient) GetSitePubStatus(ctx context.Context, siteId string) (string, string, error) {
+	pubOps, err := c.GetPubOps(ctx, siteId, false, "")
 	if err != nil {
 		return "", "", err
 	}
@@ -107,7 +114,7 @@ func (c *PublishOpsClient) GetSitePubStatus(siteId string) (string, string, erro
 }
 
 // GetPubOps - Returns list of publishing operations
-func (c *PublishOpsClient) GetPubOps(siteId string, isActive bool, publishState string) ([]api.PubOp, error) {
+func (c *PublishOpsClient) GetPubOps(ctx context.Context, siteId string, isActive bool, publishState string) ([]api.PubOp, error) {
 	if siteId == "" {
 		return nil, fmt.Errorf("siteId is empty")
 	}
@@ -126,7 +133,7 @@ func (c *PublishOpsClient) GetPubOps(siteId string, isActive bool, publishState
 	}
 	base.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", base.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", base.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -146,12 +153,12 @@ func (c *PublishOpsClient) GetPubOps(siteId string, isActive bool, publishState
 }
 
 // GetPubOp - Returns details on a publishing operation
-func (c *PublishOpsClient) GetPubOp(siteId string, publishId string) (*api.PubOp, error) {
+func (c *PublishOpsClient) GetPubOp(ctx context.Context, siteId string, publishId string) (*api.PubOp, error) {
 	if siteId == "" || publishId == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s publishId=%s", siteId, publishId)
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/%s", c.apiEndpoint, siteId, publishId), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/%s", c.apiEndpoint, siteId, publishId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +178,8 @@ func (c *PublishOpsClient) GetPubOp(siteId string, publishId string) (*api.PubOp
 }
 
 // GetAndWaitForPubOpAcceptance - Returns details about a publishing operation after waiting for it to complete validation step.
-func (c *PublishOpsClient) GetAndWaitForPubOpAcceptance(siteId string, publishId string, timeout time.Duration) (*api.PubOp, error) {
+// The operation is polled as paced by PollInterval and PollMaxInterval, for up to timeout or until ctx is done.
+func (c *PublishOpsClient) GetAndWaitForPubOpAcceptance(ctx context.Context, siteId string, publishId string, timeout time.Duration) (*api.PubOp, error) {
 	if siteId == "" || publishId == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s publishId=%s", siteId, publishId)
 	}
@@ -180,8 +188,8 @@ func (c *PublishOpsClient) GetAndWaitForPubOpAcceptance(siteId string, publishId
 	var pubOpGetResp *api.PubOp
 	var err error
 
-	for time.Since(start) < timeout {
-		pubOpGetResp, err = c.GetPubOp(siteId, publishId)
+	for poll := 0; time.Since(start) < timeout; poll++ {
+		pubOpGetResp, err = c.GetPubOp(ctx, siteId, publishId)
 
 		if err != nil {
 			return nil, err
@@ -190,13 +198,61 @@ func (c *PublishOpsClient) GetAndWaitForPubOpAcceptance(siteId string, publishId
 		if pubOpGetResp.PublishAcceptanceStatus != ACCEPTANCE_STATUS_PENDING {
 			return pubOpGetResp, nil
 		}
-		time.Sleep(3 * time.Second) // Wait for few seconds before checking again
+
+		// Wait before checking again, backing off while the validation goes on, unless the caller gave up
+		wait := min(c.pollWait(poll), max(timeout-time.Since(start), 0))
+		if err := sleepContext(ctx, wait); err != nil {
+			return pubOpGetResp, fmt.Errorf("Stopped waiting for acceptance status for siteId=%s publishId=%s: %w", siteId, publishId, err)
+		}
 	}
-	return pubOpGetResp, fmt.Errorf("Publish Operation TimedOut waiting for acceptance status for siteId=%s publishId=%s", siteId, publishId)
+	return pubOpGetResp, fmt.Errorf("Publish Operation TimedOut waiting for acceptance status for siteId=%s publishId=%s: %w", siteId, publishId, context.DeadlineExceeded)
+}
+
+// GetAndWaitForPubOpCompletion - Returns details about a publishing operation after waiting for it to be deployed,
+// that is until its publish status is no longer InProgress. Callers check for Success, Failed or Aborted.
+// The progress reported in the status line is logged along the way.
+func (c *PublishOpsClient) GetAndWaitForPubOpCompletion(ctx context.Context, siteId string, publishId string, timeout time.Duration) (*api.PubOp, error) {
+	if siteId == "" || publishId == "" {
+		return nil, fmt.Errorf("Invalid input, siteId=%s publishId=%s", siteId, publishId)
+	}
+
+	start := time.Now()
+	var pubOpGetResp *api.PubOp
+	var err error
+	var lastStatusLine string
+
+	for poll := 0; time.Since(start) < timeout; poll++ {
+		pubOpGetResp, err = c.GetPubOp(ctx, siteId, publishId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if statusLine := strings.Join(pubOpGetResp.StatusLine, ", "); statusLine != lastStatusLine {
+			tflog.Info(ctx, "Qwilt CDN publishing operation in progress: "+statusLine, map[string]any{
+				"site_id":        siteId,
+				"publish_id":     publishId,
+				"publish_status": pubOpGetResp.PublishStatus,
+				"publish_state":  pubOpGetResp.PublishState,
+			})
+			lastStatusLine = statusLine
+		}
+
+		if pubOpGetResp.PublishStatus != PUBLISH_STATUS_IN_PROGRESS {
+			return pubOpGetResp, nil
+		}
+
+		// Wait before checking again, backing off while the deployment goes on, unless the caller gave up
+		wait := min(c.pollWait(poll), max(timeout-time.Since(start), 0))
+		if err := sleepContext(ctx, wait); err != nil {
+			return pubOpGetResp, fmt.Errorf("Stopped waiting for completion of siteId=%s publishId=%s: %w", siteId, publishId, err)
+		}
+	}
+	return pubOpGetResp, fmt.Errorf("Publish Operation TimedOut waiting for completion of siteId=%s publishId=%s: %w", siteId, publishId, context.DeadlineExceeded)
 }
 
 // Publish - Publish a site
-func (c *PublishOpsClient) Publish(siteId string, revisionId string, target string) (*api.PubOp, error) {
+func (c *PublishOpsClient) Publish(ctx context.Context, siteId string, revisionId string, target string) (*api.PubOp, error) {
 	if siteId == "" || revisionId == "" || target == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s revisionId=%s target=%s", siteId, revisionId, target)
 	}
@@ -205,12 +261,7 @@ func (c *PublishOpsClient) Publish(siteId string, revisionId string, target stri
 	pubReq.RevisionId = revisionId
 	pubReq.Target = target
 
-	rb, err := json.Marshal(pubReq)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations", c.apiEndpoint, siteId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations", c.apiEndpoint, siteId), pubReq)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +281,7 @@ func (c *PublishOpsClient) Publish(siteId string, revisionId string, target stri
 }
 
 // Unpublish - Unpublish a site
-func (c *PublishOpsClient) Unpublish(siteId string, target string) (*api.PubOp, error) {
+func (c *PublishOpsClient) Unpublish(ctx context.Context, siteId string, target string) (*api.PubOp, error) {
 	if siteId == "" || target == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s target=%s", siteId, target)
 	}
@@ -238,12 +289,7 @@ func (c *PublishOpsClient) Unpublish(siteId string, target string) (*api.PubOp,
 	unpubReq := api.UnpubRequest{}
 	unpubReq.Target = target
 
-	rb, err := json.Marshal(unpubReq)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/actions/un-publish", c.apiEndpoint, siteId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/actions/un-publish", c.apiEndpoint, siteId), unpubReq)
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +309,7 @@ func (c *PublishOpsClient) Unpublish(siteId string, target string) (*api.PubOp,
 }
 
 // Republish - Republish the active configuration version
-func (c *PublishOpsClient) Republish(siteId string, target string) (*api.PubOp, error) {
+func (c *PublishOpsClient) Republish(ctx context.Context, siteId string, target string) (*api.PubOp, error) {
 	if siteId == "" || target == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s target=%s", siteId, target)
 	}
@@ -271,12 +317,7 @@ func (c *PublishOpsClient) Republish(siteId string, target string) (*api.PubOp,
 	repubReq := api.RepubRequest{}
 	repubReq.Target = target
 
-	rb, err := json.Marshal(repubReq)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/actions/republish", c.apiEndpoint, siteId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/actions/republish", c.apiEndpoint, siteId), repubReq)
 	if err != nil {
 		return nil, err
 	}
@@ -296,12 +337,12 @@ func (c *PublishOpsClient) Republish(siteId string, target string) (*api.PubOp,
 }
 
 // Cancel - Cancel an ongoing publish operation
-func (c *PublishOpsClient) Cancel(siteId string, publishId string) error {
+func (c *PublishOpsClient) Cancel(ctx context.Context, siteId string, publishId string) error {
 	if siteId == "" || publishId == "" {
 		return fmt.Errorf("Invalid input, siteId=%s publishIdf=%s", siteId, publishId)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/%s/actions/cancel", c.apiEndpoint, siteId, publishId), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/v2/sites/%s/publishing-operations/%s/actions/cancel", c.apiEndpoint, siteId, publishId), nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,43 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterThrottlesAfterBurst(t *testing.T) {
+	limiter := newRateLimiter(20)
+
+	// The initial burst goes through without waiting
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := limiter.wait(context.Background())
+		assert.Nil(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestRateLimiterStopsOnCancelledContext(t *testing.T) {
+	limiter := newRateLimiter(0.5)
+	limiter.reserve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := limiter.wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
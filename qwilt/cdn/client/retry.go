@@ -0,0 +1,96 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const DEFAULT_MAX_RETRIES = 3
+const DEFAULT_RETRY_MAX_WAIT = 30 * time.Second
+const retryMinWait = 1 * time.Second
+
+// isRetryable - Decides whether a failed attempt may be sent again.
+// Rate-limit responses are retried for every method since the request was not processed,
+// other transient failures only for idempotent methods.
+func isRetryable(req *http.Request, res *http.Response, err error) bool {
+	if req.Context().Err() != nil || errors.Is(err, ErrCassetteMismatch) {
+		return false
+	}
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !isIdempotent(req.Method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryWait - Returns how long to wait before the next attempt.
+// A Retry-After header wins over the exponential backoff, both are capped by maxWait.
+func retryWait(attempt int, res *http.Response, maxWait time.Duration) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return min(wait, maxWait)
+		}
+	}
+
+	// Exponential backoff with full jitter
+	backoff := retryMinWait << attempt
+	if backoff <= 0 || backoff > maxWait {
+		backoff = maxWait
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// parseRetryAfter - Parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext - Waits for the given duration, returning early if the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
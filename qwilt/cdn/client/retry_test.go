@@ -0,0 +1,94 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryTestClient() *Client {
+	return &Client{
+		HTTPClient:   &http.Client{Timeout: 5 * time.Second},
+		XApiToken:    "test",
+		MaxRetries:   3,
+		RetryMaxWait: 10 * time.Millisecond,
+	}
+}
+
+func TestDoRequestRetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"siteName":"a"}`, string(body))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "PUT", server.URL, strings.NewReader(`{"siteName":"a"}`))
+	body, err := newRetryTestClient().doRequest(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", string(body))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequestDoesNotRetryNonIdempotent(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", server.URL, nil)
+	_, err := newRetryTestClient().doRequest(req)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequestRetriesRateLimitUpToMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "POST", server.URL, nil)
+	_, err := newRetryTestClient().doRequest(req)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestRetryWait(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set("Retry-After", "120")
+	assert.Equal(t, 30*time.Second, retryWait(0, res, 30*time.Second))
+
+	res.Header.Set("Retry-After", "2")
+	assert.Equal(t, 2*time.Second, retryWait(5, res, 30*time.Second))
+
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := retryWait(attempt, nil, 5*time.Second)
+		assert.LessOrEqual(t, wait, 5*time.Second)
+	}
+}
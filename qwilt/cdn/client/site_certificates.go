@@ -9,11 +9,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
 	"net/http"
-	"strings"
 )
 
 // SiteCertificatesClient -
@@ -31,7 +31,7 @@ func NewSiteCertificatesClient(target string, client *Client) *SiteCertificatesC
 }
 
 // GetSiteCertficates - Returns list of site certificates
-func (c *SiteCertificatesClient) GetSiteCertificates(siteId string, revisionId string) ([]api.SiteCertificateResponse, error) {
+func (c *SiteCertificatesClient) GetSiteCertificates(ctx context.Context, siteId string, revisionId string) ([]api.SiteCertificateResponse, error) {
 	if siteId == "" {
 		return nil, fmt.Errorf("siteId is empty")
 	}
@@ -40,7 +40,7 @@ func (c *SiteCertificatesClient) GetSiteCertificates(siteId string, revisionId s
 	if revisionId != "" {
 		querystring = fmt.Sprintf("?siteRevisionId=%s", revisionId)
 	}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/sites/%s/certificates%s", c.apiEndpoint, siteId, querystring), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/sites/%s/certificates%s", c.apiEndpoint, siteId, querystring), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -60,19 +60,19 @@ func (c *SiteCertificatesClient) GetSiteCertificates(siteId string, revisionId s
 }
 
 // LinkSiteCertificate - links a site to a certificate
-func (c *SiteCertificatesClient) LinkSiteCertificate(siteId string, certId string) (*api.SiteCertificateResponse, error) {
+func (c *SiteCertificatesClient) LinkSiteCertificate(ctx context.Context, siteId string, certId string) (*api.SiteCertificateResponse, error) {
 	if siteId == "" || certId == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s certId=%s", siteId, certId)
 	}
 
-	certsResp, err := c.GetSiteCertificates(siteId, "")
+	certsResp, err := c.GetSiteCertificates(ctx, siteId, "")
 	if err != nil {
 		return nil, err
 	}
 
 	if len(certsResp) > 0 {
 		//for now QC supports only one certificate. unlink the previously linked one
-		err := c.UnLinkSiteCertificate(siteId, certsResp[0].CertificateId)
+		err := c.UnLinkSiteCertificate(ctx, siteId, certsResp[0].CertificateId)
 		if err != nil {
 			return nil, err
 		}
@@ -81,12 +81,7 @@ func (c *SiteCertificatesClient) LinkSiteCertificate(siteId string, certId strin
 	linkReq := api.SiteCertificateLinkRequest{}
 	linkReq.CertificateId = certId
 
-	rb, err := json.Marshal(linkReq)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/sites/%s/certificates", c.apiEndpoint, siteId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/v2/sites/%s/certificates", c.apiEndpoint, siteId), linkReq)
 	if err != nil {
 		return nil, err
 	}
@@ -106,12 +101,12 @@ func (c *SiteCertificatesClient) LinkSiteCertificate(siteId string, certId strin
 }
 
 // LinkSiteCertificate - links asite to a certificate
-func (c *SiteCertificatesClient) UnLinkSiteCertificate(siteId string, certId string) error {
+func (c *SiteCertificatesClient) UnLinkSiteCertificate(ctx context.Context, siteId string, certId string) error {
 	if siteId == "" || certId == "" {
 		return fmt.Errorf("Invalid input, siteId=%s certId=%s", siteId, certId)
 	}
 
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/v2/sites/%s/certificates/%s", c.apiEndpoint, siteId, certId), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/v2/sites/%s/certificates/%s", c.apiEndpoint, siteId, certId), nil)
 	if err != nil {
 		return err
 	}
@@ -20,6 +20,7 @@ type SiteClientFacade struct {
 	*CertificatesClient
 	*CertificateSigningRequestClient
 	*CertificateTemplateClient
+	*DeviceIpsClient
 	ApiEndpoint string
 }
 
@@ -33,6 +34,7 @@ func NewSiteFacadeClient(target string, client *Client) *SiteClientFacade {
 		CertificatesClient:              NewCertificatesClient(client),
 		CertificateSigningRequestClient: NewCertificateSigningRequestClient(client),
 		CertificateTemplateClient:       NewCertificateTemplateClient(client),
+		DeviceIpsClient:                 NewDeviceIpsClient(client),
 	}
 	return &c
 }
@@ -8,11 +8,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
 	"net/http"
-	"strings"
 )
 
 // SiteConfigurationClient -
@@ -30,7 +30,7 @@ func NewSiteConfigurationClient(target string, client *Client) *SiteConfiguratio
 }
 
 // GetSiteConfigs - Returns list of site configurations
-func (c *SiteConfigurationClient) GetSiteConfigs(siteId string, truncateHostIndex bool) ([]api.SiteConfigVersion, error) {
+func (c *SiteConfigurationClient) GetSiteConfigs(ctx context.Context, siteId string, truncateHostIndex bool) ([]api.SiteConfigVersion, error) {
 	if siteId == "" {
 		return nil, fmt.Errorf("siteId is empty")
 	}
@@ -40,27 +40,23 @@ func (c *SiteConfigurationClient) GetSiteConfigs(siteId string, truncateHostInde
 		querystring = "?truncateHostIndex=true"
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/1/sites/%s/configurations%s", c.apiEndpoint, siteId, querystring), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/1/sites/%s/configurations%s", c.apiEndpoint, siteId, querystring), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	siteConfigVersions := []api.SiteConfigVersion{}
-	err = json.Unmarshal(body, &siteConfigVersions)
+	// Every revision carries its host index, decode them while downloading rather than
+	// holding the whole response and its decoded copy in memory
+	body, err := c.doStreamRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	return siteConfigVersions, nil
+	return collect(newListIterator[api.SiteConfigVersion](body))
 }
 
 // GetSiteConfig - Returns site details
-func (c *SiteConfigurationClient) GetSiteConfig(siteId string, revisionId string, truncateHostIndex bool) (*api.SiteConfigVersion, error) {
+func (c *SiteConfigurationClient) GetSiteConfig(ctx context.Context, siteId string, revisionId string, truncateHostIndex bool) (*api.SiteConfigVersion, error) {
 	if siteId == "" || revisionId == "" {
 		return nil, fmt.Errorf("Invalid input, siteId=%s revisionId=%s", siteId, revisionId)
 	}
@@ -70,7 +66,7 @@ func (c *SiteConfigurationClient) GetSiteConfig(siteId string, revisionId string
 		querystring = "?truncateHostIndex=true"
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/1/sites/%s/configurations/%s%s", c.apiEndpoint, siteId, revisionId, querystring), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/1/sites/%s/configurations/%s%s", c.apiEndpoint, siteId, revisionId, querystring), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -90,17 +86,12 @@ func (c *SiteConfigurationClient) GetSiteConfig(siteId string, revisionId string
 }
 
 // CreateSiteConfig - Add a new site configuration
-func (c *SiteConfigurationClient) CreateSiteConfig(siteId string, siteConfigVersion api.SiteConfigAddRequest) (*api.SiteConfigVersion, error) {
+func (c *SiteConfigurationClient) CreateSiteConfig(ctx context.Context, siteId string, siteConfigVersion api.SiteConfigAddRequest) (*api.SiteConfigVersion, error) {
 	if siteId == "" {
 		return nil, fmt.Errorf("siteId is empty")
 	}
 
-	rb, err := json.Marshal(siteConfigVersion)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/1/sites/%s/configurations", c.apiEndpoint, siteId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/1/sites/%s/configurations", c.apiEndpoint, siteId), siteConfigVersion)
 	if err != nil {
 		return nil, err
 	}
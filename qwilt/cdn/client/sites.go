@@ -8,13 +8,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
-	"log"
 	"net/http"
-	"strings"
 )
 
 type SiteClient struct {
@@ -31,7 +29,16 @@ func NewSiteClient(target string, client *Client) *SiteClient {
 }
 
 // GetSites - Returns list of sites
-func (c *SiteClient) GetSites(includeActiveLastPub bool, includeDeletedSites bool) ([]api.Site, error) {
+func (c *SiteClient) GetSites(ctx context.Context, includeActiveLastPub bool, includeDeletedSites bool) ([]api.Site, error) {
+	it, err := c.ListSites(ctx, includeActiveLastPub, includeDeletedSites)
+	if err != nil {
+		return nil, err
+	}
+	return collect(it)
+}
+
+// ListSites - Returns an iterator over the sites, decoded while they are downloaded
+func (c *SiteClient) ListSites(ctx context.Context, includeActiveLastPub bool, includeDeletedSites bool) (*ListIterator[api.Site], error) {
 	var param string
 	if includeActiveLastPub {
 		param = "?includePublishDetails=true"
@@ -39,39 +46,36 @@ func (c *SiteClient) GetSites(includeActiveLastPub bool, includeDeletedSites boo
 		param = ""
 	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/sites%s", c.apiEndpoint, param), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/sites%s", c.apiEndpoint, param), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
+	body, err := c.doStreamRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	sites := []api.Site{}
-	err = json.Unmarshal(body, &sites)
-	if err != nil {
-		return nil, err
+	it := newListIterator[api.Site](body)
+	if !includeDeletedSites {
+		//filter deleted sites
+		it.skip = func(site api.Site) bool { return site.IsDeleted }
 	}
+	return it, nil
+}
 
-	if includeDeletedSites {
-		return sites, nil
-	}
+// GetSite - Returns site details
+func (c *SiteClient) GetSite(ctx context.Context, siteId string, target string, includeActiveLastPub bool, includeDeletedSites bool) (*api.Site, error) {
+	return c.getSite(ctx, siteId, target, includeActiveLastPub, includeDeletedSites, c.doCachedRequest)
+}
 
-	//filter deleted sites
-	nonDeletedSites := []api.Site{}
-	for _, site := range sites {
-		if site.IsDeleted {
-			continue
-		}
-		nonDeletedSites = append(nonDeletedSites, site)
-	}
-	return nonDeletedSites, nil
+// GetSiteUncached - Returns site details, bypassing the response cache. Used where a stale answer would mislead,
+// such as the active revision checked before republishing.
+func (c *SiteClient) GetSiteUncached(ctx context.Context, siteId string, target string, includeActiveLastPub bool, includeDeletedSites bool) (*api.Site, error) {
+	return c.getSite(ctx, siteId, target, includeActiveLastPub, includeDeletedSites, c.doRequest)
 }
 
-// GetSite - Returns site details
-func (c *SiteClient) GetSite(siteId string, target string, includeActiveLastPub bool, includeDeletedSites bool) (*api.Site, error) {
+func (c *SiteClient) getSite(ctx context.Context, siteId string, target string, includeActiveLastPub bool, includeDeletedSites bool, doRequest func(*http.Request) ([]byte, error)) (*api.Site, error) {
 	if siteId == "" {
 		return nil, fmt.Errorf("siteId is empty")
 	}
@@ -88,13 +92,12 @@ func (c *SiteClient) GetSite(siteId string, target string, includeActiveLastPub
 	}
 
 	url := fmt.Sprintf("%s/api/v2/sites/%s%s", c.apiEndpoint, siteId, queryParams)
-	log.Printf("***** %s *****", url)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
+	body, err := doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -106,19 +109,14 @@ func (c *SiteClient) GetSite(siteId string, target string, includeActiveLastPub
 	}
 
 	if !includeDeletedSites && siteDetail.IsDeleted {
-		return nil, errors.New("Site was found but marked for deletion")
+		return nil, ErrSiteMarkedForDeletion
 	}
 	return &siteDetail, nil
 }
 
 // CreateSite - Create new site
-func (c *SiteClient) CreateSite(site api.SiteCreateRequest) (*api.Site, error) {
-	rb, err := json.Marshal(site)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v2/sites", c.apiEndpoint), strings.NewReader(string(rb)))
+func (c *SiteClient) CreateSite(ctx context.Context, site api.SiteCreateRequest) (*api.Site, error) {
+	req, err := newJSONRequest(ctx, "POST", fmt.Sprintf("%s/api/v2/sites", c.apiEndpoint), site)
 	if err != nil {
 		return nil, err
 	}
@@ -138,17 +136,12 @@ func (c *SiteClient) CreateSite(site api.SiteCreateRequest) (*api.Site, error) {
 }
 
 // UpdateSite - Update site details
-func (c *SiteClient) UpdateSite(siteId string, site api.SiteUpdateRequest) (*api.Site, error) {
+func (c *SiteClient) UpdateSite(ctx context.Context, siteId string, site api.SiteUpdateRequest) (*api.Site, error) {
 	if siteId == "" {
 		return nil, fmt.Errorf("siteId is empty")
 	}
 
-	rb, err := json.Marshal(site)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v2/sites/%s", c.apiEndpoint, siteId), strings.NewReader(string(rb)))
+	req, err := newJSONRequest(ctx, "PUT", fmt.Sprintf("%s/api/v2/sites/%s", c.apiEndpoint, siteId), site)
 	if err != nil {
 		return nil, err
 	}
@@ -168,14 +161,14 @@ func (c *SiteClient) UpdateSite(siteId string, site api.SiteUpdateRequest) (*api
 }
 
 // DeleteSite - Deletes a site
-func (c *SiteClient) DeleteSite(siteId string) error {
+func (c *SiteClient) DeleteSite(ctx context.Context, siteId string) error {
 	if siteId == "" {
 		return fmt.Errorf("siteId is empty")
 	}
 
 	url := fmt.Sprintf("%s/api/v2/sites/%s?permanent=true", c.apiEndpoint, siteId)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,104 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const DEFAULT_REQUEST_TIMEOUT = 40 * time.Second
+
+// TransportConfig - Settings of the HTTP transport used to reach the Qwilt APIs
+type TransportConfig struct {
+	// ProxyUrl is used for all requests when set, otherwise the HTTPS_PROXY/HTTP_PROXY/NO_PROXY env variables apply
+	ProxyUrl string
+	// CaBundle is a PEM file path or PEM content, trusted in addition to the system roots
+	CaBundle           string
+	InsecureSkipVerify bool
+	// ClientCertificate and ClientKey are PEM file paths or PEM content, used for mTLS
+	ClientCertificate string
+	ClientKey         string
+	Timeout           time.Duration
+}
+
+// NewHTTPClient - Builds an HTTP client according to the transport settings
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyUrl != "" {
+		proxyUrl, err := url.Parse(cfg.ProxyUrl)
+		if err != nil || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.ProxyUrl)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CaBundle != "" {
+		caPem, err := readPem(cfg.CaBundle)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("CA bundle does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertificate != "" || cfg.ClientKey != "" {
+		if cfg.ClientCertificate == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key are required for mTLS")
+		}
+		certPem, err := readPem(cfg.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client certificate: %w", err)
+		}
+		keyPem, err := readPem(cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPem, keyPem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DEFAULT_REQUEST_TIMEOUT
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// readPem - Returns the PEM content itself, or the content of the file it points to
+func readPem(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
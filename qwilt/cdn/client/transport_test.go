@@ -0,0 +1,60 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPClientTrustsCaBundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// Not trusted by default
+	httpClient, err := NewHTTPClient(TransportConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, DEFAULT_REQUEST_TIMEOUT, httpClient.Timeout)
+	_, err = httpClient.Get(server.URL)
+	assert.NotNil(t, err)
+
+	caPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	httpClient, err = NewHTTPClient(TransportConfig{CaBundle: string(caPem), Timeout: 5 * time.Second})
+	assert.Nil(t, err)
+	res, err := httpClient.Get(server.URL)
+	assert.Nil(t, err)
+	res.Body.Close()
+
+	httpClient, err = NewHTTPClient(TransportConfig{InsecureSkipVerify: true})
+	assert.Nil(t, err)
+	res, err = httpClient.Get(server.URL)
+	assert.Nil(t, err)
+	res.Body.Close()
+}
+
+func TestNewHTTPClientInvalidSettings(t *testing.T) {
+	_, err := NewHTTPClient(TransportConfig{CaBundle: "-----BEGIN CERTIFICATE-----\ngarbage\n-----END CERTIFICATE-----"})
+	assert.NotNil(t, err)
+
+	_, err = NewHTTPClient(TransportConfig{CaBundle: "/does/not/exist.pem"})
+	assert.NotNil(t, err)
+
+	_, err = NewHTTPClient(TransportConfig{ClientCertificate: "/tmp/cert.pem"})
+	assert.NotNil(t, err)
+
+	_, err = NewHTTPClient(TransportConfig{ProxyUrl: "::not a url"})
+	assert.NotNil(t, err)
+}
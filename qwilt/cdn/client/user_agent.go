@@ -0,0 +1,61 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const USER_AGENT_PRODUCT = "terraform-provider-qwilt"
+
+// CORRELATION_ID_HEADER - Identifies the requests of one provider run, so Qwilt support can
+// find all the requests of a failed run
+const CORRELATION_ID_HEADER = "X-Correlation-Id"
+
+// UserAgent - Builds the User-Agent of the provider, such as
+// "terraform-provider-qwilt/1.2.0 terraform/1.9.5 my-pipeline".
+// Empty parts are left out.
+func UserAgent(providerVersion string, terraformVersion string, suffix string) string {
+	parts := []string{USER_AGENT_PRODUCT}
+	if providerVersion != "" {
+		parts[0] += "/" + providerVersion
+	}
+	if terraformVersion != "" {
+		parts = append(parts, "terraform/"+terraformVersion)
+	}
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		parts = append(parts, suffix)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetUserAgent - Sets the User-Agent sent with every request
+func (c *Client) SetUserAgent(userAgent string) {
+	c.userAgent = userAgent
+}
+
+// CorrelationId - Returns the ID sent with every request of this client in the X-Correlation-Id header
+func (c *Client) CorrelationId() string {
+	return c.correlationId
+}
+
+// newCorrelationId - Returns a random ID in the UUID v4 format
+func newCorrelationId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:])
+}
@@ -0,0 +1,51 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgent(t *testing.T) {
+	assert.Equal(t, "terraform-provider-qwilt/1.2.0 terraform/1.9.5 nightly-pipeline", UserAgent("1.2.0", "1.9.5", " nightly-pipeline "))
+	assert.Equal(t, "terraform-provider-qwilt/dev terraform/1.9.5", UserAgent("dev", "1.9.5", ""))
+	assert.Equal(t, "terraform-provider-qwilt", UserAgent("", "", ""))
+}
+
+func TestRequestsCarryUserAgentAndCorrelationId(t *testing.T) {
+	userAgents := []string{}
+	correlationIds := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		correlationIds = append(correlationIds, r.Header.Get(CORRELATION_ID_HEADER))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("prod", "", "", "test")
+	c.SetUserAgent(UserAgent("1.2.0", "1.9.5", "nightly"))
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		_, err := c.doRequest(req)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, []string{"terraform-provider-qwilt/1.2.0 terraform/1.9.5 nightly", "terraform-provider-qwilt/1.2.0 terraform/1.9.5 nightly"}, userAgents)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), correlationIds[0])
+	assert.Equal(t, correlationIds[0], correlationIds[1])
+
+	// Each provider run has its own ID
+	other, _ := NewClient("prod", "", "", "test")
+	assert.NotEqual(t, c.CorrelationId(), other.CorrelationId())
+}
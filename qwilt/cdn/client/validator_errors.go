@@ -0,0 +1,40 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+)
+
+// ParseValidatorErrors - Decodes the validatorsErrDetails of a publishing operation, a list of api.ValidatorError,
+// into one entry per failure. A payload of any other shape is returned whole as the message of a single entry.
+// Returns nil when there is no failure.
+func ParseValidatorErrors(details json.RawMessage) []api.ValidatorError {
+	details = bytes.TrimSpace(details)
+	if len(details) == 0 || bytes.Equal(details, []byte("null")) {
+		return nil
+	}
+
+	var validatorErrors []api.ValidatorError
+	if err := json.Unmarshal(details, &validatorErrors); err != nil {
+		return []api.ValidatorError{{Message: string(details)}}
+	}
+	for _, validatorError := range validatorErrors {
+		if validatorError.Message == "" {
+			return []api.ValidatorError{{Message: string(details)}}
+		}
+	}
+	if len(validatorErrors) == 0 {
+		return nil
+	}
+	return validatorErrors
+}
@@ -0,0 +1,50 @@
+// Package client
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValidatorErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		details  string
+		expected []api.ValidatorError
+	}{
+		{name: "empty", details: ``, expected: nil},
+		{name: "null", details: `null`, expected: nil},
+		{name: "empty list", details: `[]`, expected: nil},
+		{
+			name:    "list",
+			details: `[{"validator":"origin","host":"www.example.com","path":"/hosts/0/origin","message":"invalid origin"},{"message":"second"}]`,
+			expected: []api.ValidatorError{
+				{Validator: "origin", Host: "www.example.com", Path: "/hosts/0/origin", Message: "invalid origin"},
+				{Message: "second"},
+			},
+		},
+		{
+			name:     "other key names",
+			details:  `{"errors":[{"validatorName":"tls","errorMessage":"no certificate"}]}`,
+			expected: []api.ValidatorError{{Message: `{"errors":[{"validatorName":"tls","errorMessage":"no certificate"}]}`}},
+		},
+		{name: "plain text", details: `"something failed"`, expected: []api.ValidatorError{{Message: `"something failed"`}}},
+		{name: "invalid JSON", details: `oops`, expected: []api.ValidatorError{{Message: "oops"}}},
+		{name: "entry without message", details: `[{"message":"first"},{"code":42}]`, expected: []api.ValidatorError{{Message: `[{"message":"first"},{"code":42}]`}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ParseValidatorErrors(json.RawMessage(test.details)))
+		})
+	}
+}
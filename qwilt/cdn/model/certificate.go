@@ -18,6 +18,8 @@ type Certificate struct {
 	Domain      types.String `tfsdk:"domain"`
 	Status      types.String `tfsdk:"status"`
 	Type        types.String `tfsdk:"type"`
+
+	ForceOverwrite types.Bool `tfsdk:"force_overwrite"`
 }
 
 type CertificateBuilder struct {
@@ -72,6 +74,11 @@ func (b *CertificateBuilder) Type(description string) *CertificateBuilder {
 	return b
 }
 
+func (b *CertificateBuilder) ForceOverwrite(value types.Bool) *CertificateBuilder {
+	b.cert.ForceOverwrite = value
+	return b
+}
+
 func (b *CertificateBuilder) Build() Certificate {
 	return b.cert
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -18,6 +19,7 @@ type CertificateTemplate struct {
 	AutoManagedCertificateTemplate types.Bool     `tfsdk:"auto_managed_certificate_template"`
 	LastCertificateID              types.Int64    `tfsdk:"last_certificate_id"`
 	CsrIds                         types.List     `tfsdk:"csr_ids"`
+	Timeouts                       timeouts.Value `tfsdk:"timeouts"`
 }
 
 type CertificateTemplateBuilder struct {
@@ -90,6 +92,11 @@ func (b *CertificateTemplateBuilder) AddCsrIds(csrIds ...int64) *CertificateTemp
 	return b
 }
 
+func (b *CertificateTemplateBuilder) Timeouts(value timeouts.Value) *CertificateTemplateBuilder {
+	b.cert.Timeouts = value
+	return b
+}
+
 func (b *CertificateTemplateBuilder) Build() CertificateTemplate {
 	return b.cert
 }
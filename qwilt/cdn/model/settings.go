@@ -6,4 +6,24 @@ type Settings struct {
 	Username  string `tfsdk:"username"`
 	Password  string `tfsdk:"password"`
 	XApiToken string `tfsdk:"token"`
+
+	// HTTP
+	MaxRetries         int64  `tfsdk:"max_retries"`
+	RetryMaxWait       string `tfsdk:"retry_max_wait"`
+	HttpProxy          string `tfsdk:"http_proxy"`
+	CaBundle           string `tfsdk:"ca_bundle"`
+	InsecureSkipVerify bool   `tfsdk:"insecure_skip_verify"`
+	ClientCertificate  string `tfsdk:"client_certificate"`
+	ClientKey          string `tfsdk:"client_key"`
+	RequestTimeout     string `tfsdk:"request_timeout"`
+
+	MaxRequestsPerSecond float64 `tfsdk:"max_requests_per_second"`
+	ResponseCacheTtl     string  `tfsdk:"response_cache_ttl"`
+	GzipRequests         bool    `tfsdk:"gzip_requests"`
+	PollInterval         string  `tfsdk:"poll_interval"`
+	PollMaxInterval      string  `tfsdk:"poll_max_interval"`
+	UserAgentSuffix      string  `tfsdk:"user_agent_suffix"`
+
+	// Base URL overrides, keyed by service hostname
+	Endpoints map[string]string `tfsdk:"endpoints"`
 }
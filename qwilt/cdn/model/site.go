@@ -13,6 +13,7 @@ type Site struct {
 	SiteName                     types.String `tfsdk:"site_name"`
 	RoutingMethod                types.String `tfsdk:"routing_method"`
 	LastUpdateTimeMilli          types.Int64  `tfsdk:"last_update_time_milli"`
+	ForceOverwrite               types.Bool   `tfsdk:"force_overwrite"`
 }
 
 type SiteBuilder struct {
@@ -54,6 +55,10 @@ func (b *SiteBuilder) SiteDnsCnameDelegationTarget(value string) *SiteBuilder {
 	b.site.SiteDnsCnameDelegationTarget = types.StringValue(value)
 	return b
 }
+func (b *SiteBuilder) ForceOverwrite(value types.Bool) *SiteBuilder {
+	b.site.ForceOverwrite = value
+	return b
+}
 func (b *SiteBuilder) Build() Site {
 	return b.site
 }
@@ -2,30 +2,44 @@ package model
 
 import (
 	"context"
-	"encoding/json"
 
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type SiteActivation struct {
-	Id                      types.String `tfsdk:"id"`
-	SiteId                  types.String `tfsdk:"site_id"`
-	RevisionId              types.String `tfsdk:"revision_id"`
-	CertificateId           types.Int64  `tfsdk:"certificate_id"`
-	CertificateTemplateId   types.Int64  `tfsdk:"certificate_template_id"`
-	PublishId               types.String `tfsdk:"publish_id"`
-	CreationTimeMilli       types.Int64  `tfsdk:"creation_time_milli"`
-	OwnerOrgId              types.String `tfsdk:"owner_org_id"`
-	LastUpdateTimeMilli     types.Int64  `tfsdk:"last_update_time_milli"`
-	Target                  types.String `tfsdk:"target"`
-	Username                types.String `tfsdk:"username"`
-	PublishState            types.String `tfsdk:"publish_state"`
-	PublishStatus           types.String `tfsdk:"publish_status"`
-	PublishAcceptanceStatus types.String `tfsdk:"publish_acceptance_status"`
-	OperationType           types.String `tfsdk:"operation_type"`
-	//StatusLine          []types.String `tfsdk:"status_line"`
-	IsActive           types.Bool   `tfsdk:"is_active"`
-	ValidateErrDetails types.String `tfsdk:"validators_err_details"`
+	Id                      types.String   `tfsdk:"id"`
+	SiteId                  types.String   `tfsdk:"site_id"`
+	RevisionId              types.String   `tfsdk:"revision_id"`
+	CertificateId           types.Int64    `tfsdk:"certificate_id"`
+	CertificateTemplateId   types.Int64    `tfsdk:"certificate_template_id"`
+	PublishId               types.String   `tfsdk:"publish_id"`
+	CreationTimeMilli       types.Int64    `tfsdk:"creation_time_milli"`
+	OwnerOrgId              types.String   `tfsdk:"owner_org_id"`
+	LastUpdateTimeMilli     types.Int64    `tfsdk:"last_update_time_milli"`
+	Target                  types.String   `tfsdk:"target"`
+	Username                types.String   `tfsdk:"username"`
+	PublishState            types.String   `tfsdk:"publish_state"`
+	PublishStatus           types.String   `tfsdk:"publish_status"`
+	PublishAcceptanceStatus types.String   `tfsdk:"publish_acceptance_status"`
+	OperationType           types.String   `tfsdk:"operation_type"`
+	StatusLine              types.List     `tfsdk:"status_line"`
+	IsActive                types.Bool     `tfsdk:"is_active"`
+	ValidateErrDetails      types.List     `tfsdk:"validators_err_details"`
+	WaitForCompletion       types.Bool     `tfsdk:"wait_for_completion"`
+	CancelOnTimeout         types.Bool     `tfsdk:"cancel_on_timeout"`
+	RepublishTriggers       types.Map      `tfsdk:"republish_triggers"`
+	Timeouts                timeouts.Value `tfsdk:"timeouts"`
+}
+
+// ValidatorErrorAttrTypes - The attributes of one failure of the validation of a publishing operation
+var ValidatorErrorAttrTypes = map[string]attr.Type{
+	"validator": types.StringType,
+	"host":      types.StringType,
+	"path":      types.StringType,
+	"message":   types.StringType,
 }
 
 type SiteActivationBuilder struct {
@@ -113,20 +127,45 @@ func (b *SiteActivationBuilder) IsActive(value bool) *SiteActivationBuilder {
 	return b
 }
 
-//	func (b *SiteActivationBuilder) StatusLine(values []string) *SiteActivationBuilder {
-//		typesStrSlice := make([]types.String, len(values))
-//		for i, s := range values {
-//			typesStrSlice[i] = types.StringValue(s)
-//		}
-//		b.activation.StatusLine = typesStrSlice
-//		return b
-//	}
+func (b *SiteActivationBuilder) StatusLine(values []string) *SiteActivationBuilder {
+	typesStrSlice := make([]attr.Value, len(values))
+	for i, s := range values {
+		typesStrSlice[i] = types.StringValue(s)
+	}
+	b.activation.StatusLine = types.ListValueMust(types.StringType, typesStrSlice)
+	return b
+}
 func (b *SiteActivationBuilder) Target(value string) *SiteActivationBuilder {
 	b.activation.Target = types.StringValue(value)
 	return b
 }
-func (b *SiteActivationBuilder) ValidateErrDetails(value json.RawMessage) *SiteActivationBuilder {
-	b.activation.ValidateErrDetails = types.StringValue(string(value))
+func (b *SiteActivationBuilder) ValidateErrDetails(values []api.ValidatorError) *SiteActivationBuilder {
+	validatorErrors := make([]attr.Value, len(values))
+	for i, value := range values {
+		validatorErrors[i] = types.ObjectValueMust(ValidatorErrorAttrTypes, map[string]attr.Value{
+			"validator": types.StringValue(value.Validator),
+			"host":      types.StringValue(value.Host),
+			"path":      types.StringValue(value.Path),
+			"message":   types.StringValue(value.Message),
+		})
+	}
+	b.activation.ValidateErrDetails = types.ListValueMust(types.ObjectType{AttrTypes: ValidatorErrorAttrTypes}, validatorErrors)
+	return b
+}
+func (b *SiteActivationBuilder) WaitForCompletion(value types.Bool) *SiteActivationBuilder {
+	b.activation.WaitForCompletion = value
+	return b
+}
+func (b *SiteActivationBuilder) CancelOnTimeout(value types.Bool) *SiteActivationBuilder {
+	b.activation.CancelOnTimeout = value
+	return b
+}
+func (b *SiteActivationBuilder) RepublishTriggers(value types.Map) *SiteActivationBuilder {
+	b.activation.RepublishTriggers = value
+	return b
+}
+func (b *SiteActivationBuilder) Timeouts(value timeouts.Value) *SiteActivationBuilder {
+	b.activation.Timeouts = value
 	return b
 }
 func (b *SiteActivationBuilder) Build() SiteActivation {
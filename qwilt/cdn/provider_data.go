@@ -0,0 +1,34 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package cdn
+
+import (
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/model"
+)
+
+// ProviderData - Built once by the provider Configure and shared by all the resources and data sources,
+// so they use the same API clients, and through them the same rate limiter, response cache and sign-in.
+type ProviderData struct {
+	Client   *cdnclient.Client
+	Facade   *cdnclient.SiteClientFacade
+	Settings model.Settings
+	// SiteLocks serializes the publishing operations of a site across the activation resources
+	SiteLocks *SiteLocks
+}
+
+// NewProviderData - Builds the service clients of a configured API client
+func NewProviderData(client *cdnclient.Client, settings model.Settings) *ProviderData {
+	return &ProviderData{
+		Client:    client,
+		Facade:    cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, client),
+		Settings:  settings,
+		SiteLocks: NewSiteLocks(),
+	}
+}
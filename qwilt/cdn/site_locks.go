@@ -0,0 +1,64 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package cdn
+
+import (
+	"context"
+	"sync"
+)
+
+// SiteLocks - Serializes the publishing operations of each site, as the API refuses to start one
+// while another is in progress for the same site. Different sites are not serialized.
+type SiteLocks struct {
+	mu    sync.Mutex
+	locks map[string]*siteLock
+}
+
+type siteLock struct {
+	// held has a value while the lock is held, so waiting can be canceled
+	held chan struct{}
+	refs int
+}
+
+// NewSiteLocks - Creates the locks of the sites, shared by the resources of a provider
+func NewSiteLocks() *SiteLocks {
+	return &SiteLocks{locks: map[string]*siteLock{}}
+}
+
+// Lock - Waits until no other publishing operation of the site is running, or ctx is done.
+// Returns the function releasing the lock.
+func (l *SiteLocks) Lock(ctx context.Context, siteId string) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[siteId]
+	if !ok {
+		lock = &siteLock{held: make(chan struct{}, 1)}
+		l.locks[siteId] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+		return func() {
+			<-lock.held
+			l.release(siteId, lock)
+		}, nil
+	case <-ctx.Done():
+		l.release(siteId, lock)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *SiteLocks) release(siteId string, lock *siteLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, siteId)
+	}
+}
@@ -0,0 +1,56 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package cdn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteLocks(t *testing.T) {
+	locks := NewSiteLocks()
+	ctx := context.Background()
+
+	unlock, err := locks.Lock(ctx, "site1")
+	assert.Nil(t, err)
+
+	// Other sites are not serialized
+	unlockOther, err := locks.Lock(ctx, "site2")
+	assert.Nil(t, err)
+	unlockOther()
+
+	// The same site waits until the lock is released, or the context is done
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = locks.Lock(waitCtx, "site1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	acquired := make(chan struct{})
+	go func() {
+		unlockNext, err := locks.Lock(ctx, "site1")
+		assert.Nil(t, err)
+		close(acquired)
+		unlockNext()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-acquired
+
+	assert.Eventually(t, func() bool {
+		locks.mu.Lock()
+		defer locks.mu.Unlock()
+		return len(locks.locks) == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,101 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package cdn
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
+)
+
+const CASSETTES_DIR = "testdata/cassettes"
+
+// CASSETTE_MODE_ENV - Records or replays the API traffic of the acceptance tests, see setupTestApi
+const CASSETTE_MODE_ENV = "QCDN_CASSETTE_MODE"
+
+// cassetteEndpointEnvs - The environment variables overriding the endpoint of each Qwilt API in the provider
+var cassetteEndpointEnvs = map[string]string{
+	api.SITES_HOSTNAME:        "QCDN_ENDPOINT_MEDIA_SITES",
+	api.CERT_MANAGER_HOSTNAME: "QCDN_ENDPOINT_CERT_MANAGER",
+	api.DEVICE_IP_HOSTNAME:    "QCDN_ENDPOINT_DEVICE_IP",
+	api.LOGIN_HOSTNAME:        "QCDN_ENDPOINT_LOGIN",
+}
+
+// setupTestApi - Chooses the Qwilt API the provider started by terraform talks to:
+//   - QCDN_CASSETTE_MODE=record: the real environment, recording the traffic to testdata/cassettes/<test>.jsonl
+//   - QCDN_CASSETTE_MODE=replay: no API at all, the recorded traffic is replayed
+//   - otherwise: the real environment
+//
+// The provider runs in its own process, so the cassette sits in a proxy of each API started by the test,
+// and the provider is pointed at the proxies with the QCDN_ENDPOINT_* environment variables.
+func setupTestApi(t *testing.T) {
+	mode := os.Getenv(CASSETTE_MODE_ENV)
+	if mode == "" {
+		return
+	}
+
+	path, err := filepath.Abs(filepath.Join(CASSETTES_DIR, t.Name()+".jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to locate cassette: %s", err)
+	}
+	if mode == cdnclient.CASSETTE_MODE_RECORD {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create cassettes directory: %s", err)
+		}
+	}
+	cassette, err := cdnclient.NewCassette(mode, path)
+	if err != nil {
+		t.Fatalf("Failed to start cassette: %s", err)
+	}
+
+	envType := os.Getenv("QCDN_ENVTYPE")
+	if envType == "" {
+		envType = "prod"
+	}
+	endpoints := cdnclient.NewEndpointBuilder(envType)
+	for service, env := range cassetteEndpointEnvs {
+		upstream, err := url.Parse(endpoints.Build(service))
+		if override := os.Getenv(env); override != "" {
+			upstream, err = url.Parse(override)
+		}
+		if err != nil {
+			t.Fatalf("Invalid endpoint of %s: %s", service, err)
+		}
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetURL(upstream)
+			},
+			Transport: cassette.Transport(nil),
+			// A request the cassette cannot answer fails the test, the provider must not retry it
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			},
+		}
+		server := httptest.NewServer(proxy)
+		t.Cleanup(server.Close)
+		t.Setenv(env, server.URL)
+	}
+
+	if mode == cdnclient.CASSETTE_MODE_REPLAY {
+		// Replayed requests never reach the API, any credentials will do, and nothing is worth waiting for
+		if os.Getenv("QCDN_API_KEY") == "" && os.Getenv("QCDN_USERNAME") == "" {
+			t.Setenv("QCDN_API_KEY", "replay-api-key")
+		}
+		t.Setenv("QCDN_POLL_INTERVAL", "10ms")
+	}
+
+	testRand = rand.New(rand.NewSource(cassette.Seed))
+}
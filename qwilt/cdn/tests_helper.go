@@ -336,13 +336,14 @@ func findStateResource(state *tfjson.State, resourceType string, name string) *t
 //	return nil
 //}
 
-func randString(length int) string {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
+// testRand generates the random names of the tests, cassette runs seed it so that recording and replaying use the same names
+var testRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 
+func randString(length int) string {
 	charSet := "abcdefghijklmnopqrstuvwxyz0123456789"
 	var result string
 	for i := 0; i < length; i++ {
-		result += string(charSet[rand.Intn(len(charSet))])
+		result += string(charSet[testRand.Intn(len(charSet))])
 	}
 	return result
 }
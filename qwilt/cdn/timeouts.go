@@ -0,0 +1,54 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package cdn
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// DEFAULT_PUBLISH_TIMEOUT - How long a site activation may take, including the validation of the publishing operation
+const DEFAULT_PUBLISH_TIMEOUT = cdnclient.ACCEPTANCE_TIMEOUT
+
+// PUBLISH_CANCEL_TIMEOUT - How long canceling a publishing operation may take, after its own timeout
+const PUBLISH_CANCEL_TIMEOUT = 30 * time.Second
+
+// DEFAULT_CERTIFICATE_TEMPLATE_TIMEOUT - How long a certificate template operation may take
+const DEFAULT_CERTIFICATE_TEMPLATE_TIMEOUT = 5 * time.Minute
+
+// timeoutsBlock - The timeouts block of a resource, with the operations enabled in opts.
+// Each operation gives up after its timeout, or after defaultTimeout when not set.
+func timeoutsBlock(ctx context.Context, opts timeouts.Opts, defaultTimeout time.Duration) schema.Block {
+	defaultValue := formatTimeout(defaultTimeout)
+	description := func(operation string) string {
+		return "How long to wait for the " + operation + " operation to complete, as a duration string such as \"" + defaultValue + "\". Defaults to " + defaultValue + "."
+	}
+	opts.CreateDescription = description("create")
+	opts.UpdateDescription = description("update")
+	opts.DeleteDescription = description("delete")
+	block := timeouts.Block(ctx, opts).(schema.SingleNestedBlock)
+	block.Description = "Timeouts of the operations of this resource."
+	return block
+}
+
+// formatTimeout - Formats a timeout the way it is usually written, "20m" rather than "20m0s"
+func formatTimeout(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
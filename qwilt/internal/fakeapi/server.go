@@ -0,0 +1,929 @@
+// Package fakeapi
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+
+// Package fakeapi is an in-process stand-in for the Qwilt CDN APIs (media-sites,
+// cert-manager, device-ip and login), keeping its state in memory so the client,
+// resources and data sources can be tested offline.
+package fakeapi
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+)
+
+const ORG_ID = "devorg"
+const USERNAME = "terraform@fake.qwilt.com"
+const LOGIN_TOKEN = "fake-login-token"
+
+// Publishing operation states, each read of a pending operation moves it one step forward
+const (
+	PUB_STATE_VALIDATING = "Validating"
+	PUB_STATE_DEPLOYING  = "Deploying"
+	PUB_STATE_DONE       = "Done"
+	PUB_STATE_FAILED     = "Failed"
+	PUB_STATE_CANCELED   = "Canceled"
+)
+
+// Server - A fake of the Qwilt APIs, all services are served from the same URL
+type Server struct {
+	*httptest.Server
+
+	mu               sync.Mutex
+	nextId           int64
+	sites            map[string]*site
+	certificates     map[int64]*certificate
+	templates        map[int64]*api.CertificateTemplate
+	csrs             map[int64]*api.CertificateSigningRequest
+	rejectedRevision map[string]json.RawMessage
+}
+
+type site struct {
+	api.Site
+	configs []api.SiteConfigVersion
+	pubOps  []*api.PubOp
+	// certIds are the certificates linked to the site
+	certIds []string
+}
+
+type certificate struct {
+	api.Certificate
+	privateKey string
+}
+
+// NewServer - Starts a fake Qwilt API server, to be closed by the caller
+func NewServer() *Server {
+	s := &Server{
+		sites:            map[string]*site{},
+		certificates:     map[int64]*certificate{},
+		templates:        map[int64]*api.CertificateTemplate{},
+		csrs:             map[int64]*api.CertificateSigningRequest{},
+		rejectedRevision: map[string]json.RawMessage{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /login", s.login)
+
+	mux.HandleFunc("GET /api/v2/sites", s.getSites)
+	mux.HandleFunc("POST /api/v2/sites", s.createSite)
+	mux.HandleFunc("GET /api/v2/sites/{siteId}", s.getSite)
+	mux.HandleFunc("PUT /api/v2/sites/{siteId}", s.updateSite)
+	mux.HandleFunc("DELETE /api/v2/sites/{siteId}", s.deleteSite)
+
+	mux.HandleFunc("GET /api/1/sites/{siteId}/configurations", s.getSiteConfigs)
+	mux.HandleFunc("POST /api/1/sites/{siteId}/configurations", s.createSiteConfig)
+	mux.HandleFunc("GET /api/1/sites/{siteId}/configurations/{revisionId}", s.getSiteConfig)
+
+	mux.HandleFunc("GET /api/v2/sites/{siteId}/publishing-operations", s.getPubOps)
+	mux.HandleFunc("POST /api/v2/sites/{siteId}/publishing-operations", s.publish)
+	mux.HandleFunc("GET /api/v2/sites/{siteId}/publishing-operations/{publishId}", s.getPubOp)
+	mux.HandleFunc("POST /api/v2/sites/{siteId}/publishing-operations/actions/un-publish", s.unpublish)
+	mux.HandleFunc("POST /api/v2/sites/{siteId}/publishing-operations/actions/republish", s.republish)
+	mux.HandleFunc("POST /api/v2/sites/{siteId}/publishing-operations/{publishId}/actions/cancel", s.cancel)
+
+	mux.HandleFunc("GET /api/v2/sites/{siteId}/certificates", s.getSiteCertificates)
+	mux.HandleFunc("POST /api/v2/sites/{siteId}/certificates", s.linkSiteCertificate)
+	mux.HandleFunc("DELETE /api/v2/sites/{siteId}/certificates/{certId}", s.unlinkSiteCertificate)
+
+	mux.HandleFunc("GET /api/v2/certificates", s.getCertificates)
+	mux.HandleFunc("POST /api/v2/certificates", s.createCertificate)
+	mux.HandleFunc("GET /api/v2/certificates/{certId}", s.getCertificate)
+	mux.HandleFunc("PUT /api/v2/certificates/{certId}", s.updateCertificate)
+	mux.HandleFunc("DELETE /api/v2/certificates/{certId}", s.deleteCertificate)
+
+	mux.HandleFunc("GET /api/v2/certificate-templates", s.getCertificateTemplates)
+	mux.HandleFunc("POST /api/v2/certificate-templates", s.createCertificateTemplate)
+	mux.HandleFunc("GET /api/v2/certificate-templates/{id}", s.getCertificateTemplate)
+	mux.HandleFunc("DELETE /api/v2/certificate-templates/{id}", s.deleteCertificateTemplate)
+	mux.HandleFunc("GET /api/v2/certificate-signing-requests/{id}", s.getCertificateSigningRequest)
+
+	mux.HandleFunc("GET /api/1.0/network/device-ip", s.getDeviceIps)
+
+	s.Server = httptest.NewServer(s.authenticated(mux))
+	return s
+}
+
+// Endpoints - Returns the base URL of every service, keyed like the provider endpoint overrides
+func (s *Server) Endpoints() map[string]string {
+	return map[string]string{
+		api.SITES_HOSTNAME:        s.URL,
+		api.CERT_MANAGER_HOSTNAME: s.URL,
+		api.DEVICE_IP_HOSTNAME:    s.URL,
+		api.LOGIN_HOSTNAME:        s.URL,
+	}
+}
+
+// RejectRevision - Makes every publish of the revision fail validation with the given details
+func (s *Server) RejectRevision(revisionId string, validatorsErrDetails json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejectedRevision[revisionId] = validatorsErrDetails
+}
+
+// ModifySite - Renames a site behind the provider's back, as another user of the Qwilt portal would
+func (s *Server) ModifySite(siteId string, siteName string, user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.sites[siteId]; ok {
+		st.SiteName = siteName
+		st.LastUpdateTimeMilli = later(st.LastUpdateTimeMilli)
+		st.LastUpdatedUser = user
+	}
+}
+
+// ModifyCertificate - Changes the description of a certificate behind the provider's back
+func (s *Server) ModifyCertificate(certId int64, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cert, ok := s.certificates[certId]; ok {
+		cert.Description = description
+	}
+}
+
+// RemoveSite - Deletes a site behind the provider's back
+func (s *Server) RemoveSite(siteId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sites, siteId)
+}
+
+// authenticated - Rejects requests without credentials, cleans the request path (some clients
+// build URLs with a double slash) and decodes gzip-encoded bodies
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = path.Clean(r.URL.Path)
+		r.URL.RawPath = ""
+		if r.URL.Path != "/login" && r.Header.Get("Authorization") == "" {
+			writeError(w, r, http.StatusUnauthorized, "missing credentials")
+			return
+		}
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			body, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "invalid gzip body")
+				return
+			}
+			r.Body = body
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) newId() int64 {
+	s.nextId++
+	return s.nextId
+}
+
+// newObjectId - Returns an ID formatted like the API object IDs
+func (s *Server) newObjectId() string {
+	return fmt.Sprintf("%024x", s.newId())
+}
+
+func now() int {
+	return int(time.Now().UnixMilli())
+}
+
+// later - Returns an update time after prev, even within the same millisecond
+func later(prev int) int {
+	return max(now(), prev+1)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	writeJSON(w, status, api.ErrorResponse{
+		Status:    status,
+		Error:     http.StatusText(status),
+		Message:   msg,
+		Path:      r.URL.Path,
+		Timestamp: json.RawMessage(strconv.Itoa(now())),
+	})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username == "" || password == "" {
+		writeError(w, r, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: "cqloudLoginToken", Value: LOGIN_TOKEN})
+	w.WriteHeader(http.StatusFound)
+}
+
+// Sites
+
+// lookupSite - Returns the site or writes a 404, s.mu must be held
+func (s *Server) lookupSite(w http.ResponseWriter, r *http.Request) *site {
+	st, ok := s.sites[r.PathValue("siteId")]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("site %s does not exist", r.PathValue("siteId")))
+		return nil
+	}
+	return st
+}
+
+// siteView - Returns the site as returned by the API, s.mu must be held
+func (s *Server) siteView(st *site, r *http.Request) api.Site {
+	view := st.Site
+	if r.URL.Query().Get("includePublishDetails") != "true" {
+		return view
+	}
+	target := r.URL.Query().Get("publishTarget")
+	if target == "" {
+		target = "ga"
+	}
+	details := api.ActiveLastPub{}
+	for _, op := range st.pubOps {
+		if op.Target != target {
+			continue
+		}
+		opCopy := *op
+		details.Last = &opCopy
+		if op.IsActive {
+			details.Active = &opCopy
+		}
+	}
+	view.ActiveAndLastPublishingOperation = &details
+	return view
+}
+
+func (s *Server) getSites(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sites := []api.Site{}
+	for _, st := range s.sites {
+		sites = append(sites, s.siteView(st, r))
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].SiteId < sites[j].SiteId })
+	writeJSON(w, http.StatusOK, sites)
+}
+
+func (s *Server) createSite(w http.ResponseWriter, r *http.Request) {
+	req := api.SiteCreateRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.SiteName == "" {
+		writeError(w, r, http.StatusBadRequest, "siteName is required")
+		return
+	}
+	if req.RoutingMethod == "" {
+		req.RoutingMethod = "DNS"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	siteId := s.newObjectId()
+	st := &site{Site: api.Site{
+		SiteId:                       siteId,
+		OwnerOrgId:                   ORG_ID,
+		CreationTimeMilli:            now(),
+		LastUpdateTimeMilli:          now(),
+		CreatedUser:                  USERNAME,
+		LastUpdatedUser:              USERNAME,
+		SiteDnsCnameDelegationTarget: fmt.Sprintf("edge.ds-%s-%s.global.fake.qwilt.com", siteId[len(siteId)-5:], ORG_ID),
+		SiteName:                     req.SiteName,
+		ApiVersion:                   "v2",
+		ServiceType:                  "MEDIA_DELIVERY",
+		RoutingMethod:                req.RoutingMethod,
+		ServiceId:                    s.newObjectId(),
+	}}
+	s.sites[siteId] = st
+	writeJSON(w, http.StatusCreated, st.Site)
+}
+
+func (s *Server) getSite(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st := s.lookupSite(w, r); st != nil {
+		writeJSON(w, http.StatusOK, s.siteView(st, r))
+	}
+}
+
+func (s *Server) updateSite(w http.ResponseWriter, r *http.Request) {
+	req := api.SiteUpdateRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	st.SiteName = req.SiteName
+	st.LastUpdateTimeMilli = later(st.LastUpdateTimeMilli)
+	st.LastUpdatedUser = USERNAME
+	writeJSON(w, http.StatusOK, st.Site)
+}
+
+func (s *Server) deleteSite(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st := s.lookupSite(w, r); st != nil {
+		delete(s.sites, st.SiteId)
+		writeJSON(w, http.StatusOK, st.Site)
+	}
+}
+
+// Site configurations
+
+func configView(config api.SiteConfigVersion, r *http.Request) api.SiteConfigVersion {
+	if r.URL.Query().Get("truncateHostIndex") == "true" {
+		config.HostIndex = json.RawMessage(`{}`)
+	}
+	return config
+}
+
+func (s *Server) getSiteConfigs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	configs := []api.SiteConfigVersion{}
+	for _, config := range st.configs {
+		configs = append(configs, configView(config, r))
+	}
+	writeJSON(w, http.StatusOK, configs)
+}
+
+func (s *Server) createSiteConfig(w http.ResponseWriter, r *http.Request) {
+	req := api.SiteConfigAddRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if !json.Valid(req.HostIndex) {
+		writeError(w, r, http.StatusBadRequest, "hostIndex is not a valid JSON document")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	config := api.SiteConfigVersion{
+		SiteId:              st.SiteId,
+		RevisionId:          s.newObjectId(),
+		RevisionNum:         len(st.configs) + 1,
+		OwnerOrgId:          ORG_ID,
+		CreationTimeMilli:   now(),
+		LastUpdateTimeMilli: now(),
+		CreatedUser:         USERNAME,
+		HostIndex:           req.HostIndex,
+		ChangeDescription:   req.ChangeDescription,
+	}
+	st.configs = append(st.configs, config)
+	writeJSON(w, http.StatusCreated, config)
+}
+
+func (s *Server) getSiteConfig(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	for _, config := range st.configs {
+		if config.RevisionId == r.PathValue("revisionId") {
+			writeJSON(w, http.StatusOK, configView(config, r))
+			return
+		}
+	}
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("revision %s does not exist", r.PathValue("revisionId")))
+}
+
+// Publishing operations
+
+// startPubOp - Adds a new publishing operation to the site, s.mu must be held
+func (s *Server) startPubOp(st *site, operationType string, revisionId string, target string) *api.PubOp {
+	op := &api.PubOp{
+		PublishId:               s.newObjectId(),
+		CreationTimeMilli:       now(),
+		OwnerOrgId:              ORG_ID,
+		LastUpdateTimeMilli:     now(),
+		RevisionId:              revisionId,
+		Target:                  target,
+		Username:                USERNAME,
+		PublishState:            PUB_STATE_VALIDATING,
+		PublishStatus:           "InProgress",
+		PublishAcceptanceStatus: "Pending",
+		PublishMode:             "Normal",
+		OperationType:           operationType,
+		StatusLine:              []string{"Validating configuration"},
+	}
+	st.pubOps = append(st.pubOps, op)
+	return op
+}
+
+// advancePubOp - Moves an operation one step forward: Pending -> Accepted -> Success, s.mu must be held
+func (s *Server) advancePubOp(st *site, op *api.PubOp) {
+	if op.PublishStatus != "InProgress" {
+		return
+	}
+	op.LastUpdateTimeMilli = now()
+
+	if op.PublishAcceptanceStatus == "Pending" {
+		if details, rejected := s.rejectedRevision[op.RevisionId]; rejected {
+			op.PublishAcceptanceStatus = "Invalid"
+			op.PublishStatus = "Failed"
+			op.PublishState = PUB_STATE_FAILED
+			op.ValidatorsErrDetails = details
+			op.StatusLine = []string{"Validation failed"}
+			return
+		}
+		op.PublishAcceptanceStatus = "Accepted"
+		op.PublishState = PUB_STATE_DEPLOYING
+		op.StatusLine = []string{"Validation passed", "Deploying to the CDN"}
+		return
+	}
+
+	op.PublishStatus = "Success"
+	op.PublishState = PUB_STATE_DONE
+	op.StatusLine = []string{"Validation passed", "Deployed to the CDN"}
+	for _, other := range st.pubOps {
+		if other.Target == op.Target {
+			other.IsActive = false
+		}
+	}
+	op.IsActive = op.OperationType != api.OPERATION_TYPE_UNPUBLISH
+}
+
+// isPublishing - Reports whether the site has an operation in progress for the target, s.mu must be held
+func isPublishing(st *site, target string) bool {
+	for _, op := range st.pubOps {
+		if op.Target == target && op.PublishStatus == "InProgress" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getPubOps(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	isActive := r.URL.Query().Get("isActive") != ""
+	publishState := r.URL.Query().Get("publishState")
+	pubOps := []api.PubOp{}
+	for _, op := range st.pubOps {
+		if (isActive && !op.IsActive) || (publishState != "" && op.PublishState != publishState) {
+			continue
+		}
+		pubOps = append(pubOps, *op)
+	}
+	writeJSON(w, http.StatusOK, pubOps)
+}
+
+func (s *Server) getPubOp(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	for _, op := range st.pubOps {
+		if op.PublishId == r.PathValue("publishId") {
+			s.advancePubOp(st, op)
+			writeJSON(w, http.StatusOK, *op)
+			return
+		}
+	}
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("publishing operation %s does not exist", r.PathValue("publishId")))
+}
+
+func (s *Server) publish(w http.ResponseWriter, r *http.Request) {
+	req := api.PubRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Target == "" {
+		req.Target = "ga"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	found := false
+	for _, config := range st.configs {
+		found = found || config.RevisionId == req.RevisionId
+	}
+	if !found {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("revision %s does not exist", req.RevisionId))
+		return
+	}
+	if isPublishing(st, req.Target) {
+		writeError(w, r, http.StatusConflict, "another publishing operation is in progress for this site")
+		return
+	}
+	writeJSON(w, http.StatusCreated, *s.startPubOp(st, "Publish", req.RevisionId, req.Target))
+}
+
+func (s *Server) unpublish(w http.ResponseWriter, r *http.Request) {
+	req := api.UnpubRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	if isPublishing(st, req.Target) {
+		writeError(w, r, http.StatusConflict, "another publishing operation is in progress for this site")
+		return
+	}
+	writeJSON(w, http.StatusCreated, *s.startPubOp(st, api.OPERATION_TYPE_UNPUBLISH, "", req.Target))
+}
+
+func (s *Server) republish(w http.ResponseWriter, r *http.Request) {
+	req := api.RepubRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	var active *api.PubOp
+	for _, op := range st.pubOps {
+		if op.Target == req.Target && op.IsActive {
+			active = op
+		}
+	}
+	if active == nil {
+		writeError(w, r, http.StatusBadRequest, "the site has no active revision to republish")
+		return
+	}
+	if isPublishing(st, req.Target) {
+		writeError(w, r, http.StatusConflict, "another publishing operation is in progress for this site")
+		return
+	}
+	writeJSON(w, http.StatusCreated, *s.startPubOp(st, "Publish", active.RevisionId, req.Target))
+}
+
+func (s *Server) cancel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	for _, op := range st.pubOps {
+		if op.PublishId != r.PathValue("publishId") {
+			continue
+		}
+		if op.PublishStatus != "InProgress" {
+			writeError(w, r, http.StatusBadRequest, "the publishing operation is not in progress")
+			return
+		}
+		op.PublishStatus = "Aborted"
+		op.PublishAcceptanceStatus = "Aborted"
+		op.PublishState = PUB_STATE_CANCELED
+		op.StatusLine = []string{"Canceled by user"}
+		op.LastUpdateTimeMilli = now()
+		writeJSON(w, http.StatusOK, *op)
+		return
+	}
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("publishing operation %s does not exist", r.PathValue("publishId")))
+}
+
+// Site certificates
+
+func siteCertificates(st *site) []api.SiteCertificateResponse {
+	certs := []api.SiteCertificateResponse{}
+	for _, certId := range st.certIds {
+		certs = append(certs, api.SiteCertificateResponse{
+			CertificateId:   certId,
+			CertificateType: "Customer",
+			Target:          "ga",
+			State:           "Linked",
+		})
+	}
+	return certs
+}
+
+func (s *Server) getSiteCertificates(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st := s.lookupSite(w, r); st != nil {
+		writeJSON(w, http.StatusOK, siteCertificates(st))
+	}
+}
+
+func (s *Server) linkSiteCertificate(w http.ResponseWriter, r *http.Request) {
+	req := api.SiteCertificateLinkRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	certId, _ := strconv.ParseInt(req.CertificateId, 10, 64)
+	if _, ok := s.certificates[certId]; !ok {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("certificate %s does not exist", req.CertificateId))
+		return
+	}
+	st.certIds = []string{req.CertificateId}
+	writeJSON(w, http.StatusCreated, siteCertificates(st))
+}
+
+func (s *Server) unlinkSiteCertificate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lookupSite(w, r)
+	if st == nil {
+		return
+	}
+	for i, certId := range st.certIds {
+		if certId == r.PathValue("certId") {
+			st.certIds = append(st.certIds[:i], st.certIds[i+1:]...)
+			writeJSON(w, http.StatusOK, siteCertificates(st))
+			return
+		}
+	}
+	writeError(w, r, http.StatusNotFound, fmt.Sprintf("certificate %s is not linked to the site", r.PathValue("certId")))
+}
+
+// Certificates
+
+// lookupCertificate - Returns the certificate or writes a 404, s.mu must be held
+func (s *Server) lookupCertificate(w http.ResponseWriter, r *http.Request) *certificate {
+	certId, _ := strconv.ParseInt(r.PathValue("certId"), 10, 64)
+	cert, ok := s.certificates[certId]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("certificate %s does not exist", r.PathValue("certId")))
+		return nil
+	}
+	return cert
+}
+
+// addCertificate - Stores a new certificate, s.mu must be held
+func (s *Server) addCertificate(req api.CertificateCreateRequest, csrId *string) *certificate {
+	cert := &certificate{
+		Certificate: api.Certificate{
+			CertId:           s.newId(),
+			Certificate:      req.Certificate,
+			CertificateChain: req.CertificateChain,
+			CsrId:            csrId,
+			Description:      req.Description,
+			PkHash:           fmt.Sprintf("%x", sha256.Sum256([]byte(req.PrivateKey))),
+			Tenant:           ORG_ID,
+			Status:           "Active",
+			Type:             "Customer",
+		},
+		privateKey: req.PrivateKey,
+	}
+	s.certificates[cert.CertId] = cert
+	return cert
+}
+
+func (s *Server) getCertificates(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	certs := []api.Certificate{}
+	for _, cert := range s.certificates {
+		certs = append(certs, cert.Certificate)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].CertId < certs[j].CertId })
+	writeJSON(w, http.StatusOK, certs)
+}
+
+func (s *Server) createCertificate(w http.ResponseWriter, r *http.Request) {
+	req := api.CertificateCreateRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Certificate == "" || req.PrivateKey == "" {
+		writeError(w, r, http.StatusBadRequest, "certificate and privateKey are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, s.addCertificate(req, nil).Certificate)
+}
+
+func (s *Server) getCertificate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cert := s.lookupCertificate(w, r); cert != nil {
+		writeJSON(w, http.StatusOK, cert.Certificate)
+	}
+}
+
+func (s *Server) updateCertificate(w http.ResponseWriter, r *http.Request) {
+	req := api.CertificateUpdateRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cert := s.lookupCertificate(w, r)
+	if cert == nil {
+		return
+	}
+	cert.Certificate.Certificate = req.Certificate
+	cert.CertificateChain = req.CertificateChain
+	cert.Description = req.Description
+	if req.PrivateKey != "" {
+		cert.privateKey = req.PrivateKey
+		cert.PkHash = fmt.Sprintf("%x", sha256.Sum256([]byte(req.PrivateKey)))
+	}
+	writeJSON(w, http.StatusOK, cert.Certificate)
+}
+
+func (s *Server) deleteCertificate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cert := s.lookupCertificate(w, r)
+	if cert == nil {
+		return
+	}
+	certId := strconv.FormatInt(cert.CertId, 10)
+	for _, st := range s.sites {
+		for _, linked := range st.certIds {
+			if linked == certId {
+				writeError(w, r, http.StatusConflict, fmt.Sprintf("certificate %s is linked to site %s", certId, st.SiteId))
+				return
+			}
+		}
+	}
+	delete(s.certificates, cert.CertId)
+	w.WriteHeader(http.StatusOK)
+}
+
+// Certificate templates and signing requests
+
+// lookupTemplate - Returns the certificate template or writes a 404, s.mu must be held
+func (s *Server) lookupTemplate(w http.ResponseWriter, r *http.Request) *api.CertificateTemplate {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	template, ok := s.templates[id]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("certificate template %s does not exist", r.PathValue("id")))
+		return nil
+	}
+	return template
+}
+
+func (s *Server) getCertificateTemplates(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	templates := []api.CertificateTemplate{}
+	for _, template := range s.templates {
+		templates = append(templates, *template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].CertificateTemplateID < templates[j].CertificateTemplateID
+	})
+	writeJSON(w, http.StatusOK, templates)
+}
+
+// createCertificateTemplate - Creates the template with a CSR and, when auto-managed, its signed certificate
+func (s *Server) createCertificateTemplate(w http.ResponseWriter, r *http.Request) {
+	req := api.CertificateTemplateCreateRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.CommonName == "" {
+		writeError(w, r, http.StatusBadRequest, "commonName is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template := &api.CertificateTemplate{
+		CertificateTemplateID:          s.newId(),
+		Country:                        req.Country,
+		Tenant:                         ORG_ID,
+		State:                          req.State,
+		Locality:                       req.Locality,
+		OrganizationName:               req.OrganizationName,
+		CommonName:                     req.CommonName,
+		SANs:                           req.SANs,
+		AutoManagedCertificateTemplate: req.AutoManagedCertificateTemplate,
+	}
+
+	csr := &api.CertificateSigningRequest{
+		CsrID:                    int(s.newId()),
+		Tenant:                   ORG_ID,
+		CommonName:               req.CommonName,
+		SANs:                     req.SANs,
+		SigningState:             "Pending",
+		AutoManagedCSR:           req.AutoManagedCertificateTemplate,
+		CertificateTemplateIDRef: strconv.FormatInt(template.CertificateTemplateID, 10),
+	}
+	for _, domain := range append([]string{req.CommonName}, req.SANs...) {
+		csr.ChallengeDelegationOfDomainsList = append(csr.ChallengeDelegationOfDomainsList, api.ChallengeDelegation{
+			FromDomain: "_acme-challenge." + domain,
+			ToDomain:   fmt.Sprintf("%d.acme.fake.qwilt.com", csr.CsrID),
+		})
+	}
+	s.csrs[int64(csr.CsrID)] = csr
+	template.CsrIds = []int64{int64(csr.CsrID)}
+
+	if req.AutoManagedCertificateTemplate {
+		csrId := strconv.Itoa(csr.CsrID)
+		cert := s.addCertificate(api.CertificateCreateRequest{
+			Certificate: "fake certificate for " + req.CommonName,
+			Description: "Auto-managed certificate for " + req.CommonName,
+		}, &csrId)
+		csr.SigningState = "Signed"
+		csr.LastCertificateID = int(cert.CertId)
+		template.LastCertificateID = &cert.CertId
+	}
+
+	s.templates[template.CertificateTemplateID] = template
+	writeJSON(w, http.StatusCreated, *template)
+}
+
+func (s *Server) getCertificateTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if template := s.lookupTemplate(w, r); template != nil {
+		writeJSON(w, http.StatusOK, *template)
+	}
+}
+
+func (s *Server) deleteCertificateTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if template := s.lookupTemplate(w, r); template != nil {
+		delete(s.templates, template.CertificateTemplateID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Server) getCertificateSigningRequest(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	csr, ok := s.csrs[id]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("certificate signing request %s does not exist", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, *csr)
+}
+
+// Device IPs
+
+func (s *Server) getDeviceIps(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.DeviceIpsModel{
+		Md5:              "5d41402abc4b2a76b9719d911017c592",
+		CreateTimeMillis: now(),
+		IpData: map[string]api.NetworkDeviceIpsModel{
+			"fake-network": {
+				Ipv4: []string{"192.0.2.10", "192.0.2.11"},
+				Ipv6: []string{"2001:db8::10"},
+			},
+		},
+	})
+}
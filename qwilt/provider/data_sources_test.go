@@ -0,0 +1,142 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
+	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestSitesDataSourceUnit(t *testing.T) {
+	server, providerConfig := newTestApi(t)
+
+	// Seed a site with a revision through the client, the data source reads them back
+	c, err := cdnclient.New(cdnclient.WithAPIKey("fake-api-key"), cdnclient.WithEndpoint(api.SITES_HOSTNAME, server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	facade := cdnclient.NewSiteFacadeClient(api.SITES_HOSTNAME, c)
+	site, err := facade.CreateSite(context.Background(), api.SiteCreateRequest{SiteName: "unit-test site"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = facade.CreateSiteConfig(context.Background(), site.SiteId, api.SiteConfigAddRequest{
+		HostIndex:         json.RawMessage(`{"hosts":[]}`),
+		ChangeDescription: "unit test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "qwilt_cdn_sites" "test" {
+	filter = {
+		site_id     = "` + site.SiteId + `"
+		revision_id = "all"
+		publish_id  = "all"
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qwilt_cdn_sites.test", "site.#", "1"),
+					resource.TestCheckResourceAttr("data.qwilt_cdn_sites.test", "site.0.site_name", "unit-test site"),
+					resource.TestCheckResourceAttr("data.qwilt_cdn_sites.test", "revision.#", "1"),
+					resource.TestCheckResourceAttr("data.qwilt_cdn_sites.test", "revision.0.change_description", "unit test"),
+				),
+			},
+		},
+	})
+}
+
+func TestCertificatesDataSourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_certificate" "test" {
+	certificate       = base64encode("fake certificate")
+	certificate_chain = base64encode("fake certificate chain")
+	private_key       = base64encode("fake private key")
+	description       = "unit test"
+}
+
+data "qwilt_cdn_certificates" "test" {
+	filter = {
+		cert_id = qwilt_cdn_certificate.test.cert_id
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qwilt_cdn_certificates.test", "certificate.#", "1"),
+					resource.TestCheckResourceAttrPair("data.qwilt_cdn_certificates.test", "certificate.0.cert_id", "qwilt_cdn_certificate.test", "cert_id"),
+					resource.TestCheckResourceAttr("data.qwilt_cdn_certificates.test", "certificate.0.description", "unit test"),
+				),
+			},
+		},
+	})
+}
+
+func TestCertificateTemplatesDataSourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_certificate_template" "test" {
+	common_name = "www.unit-test.example.com"
+	sans        = ["cdn.unit-test.example.com"]
+}
+
+data "qwilt_cdn_certificate_templates" "test" {
+	filter = {
+		certificate_template_id = qwilt_cdn_certificate_template.test.certificate_template_id
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qwilt_cdn_certificate_templates.test", "certificate_template.#", "1"),
+					resource.TestCheckResourceAttr("data.qwilt_cdn_certificate_templates.test", "certificate_template.0.common_name", "www.unit-test.example.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestOriginAllowListDataSourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "qwilt_cdn_origin_allow_list" "test" {
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qwilt_cdn_origin_allow_list.test", "ip_data.%", "1"),
+					resource.TestCheckResourceAttr("data.qwilt_cdn_origin_allow_list.test", "ip_data.fake-network.ipv4.#", "2"),
+				),
+			},
+		},
+	})
+}
@@ -17,4 +17,31 @@ type QwiltProviderModel struct {
 	Username  types.String `tfsdk:"username"`
 	Password  types.String `tfsdk:"password"`
 	XApiToken types.String `tfsdk:"api_key"`
+
+	// HTTP
+	MaxRetries         types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait       types.String `tfsdk:"retry_max_wait"`
+	HttpProxy          types.String `tfsdk:"http_proxy"`
+	CaBundle           types.String `tfsdk:"ca_bundle"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ClientCertificate  types.String `tfsdk:"client_certificate"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
+
+	MaxRequestsPerSecond types.Float64 `tfsdk:"max_requests_per_second"`
+	ResponseCacheTtl     types.String  `tfsdk:"response_cache_ttl"`
+	GzipRequests         types.Bool    `tfsdk:"gzip_requests"`
+	PollInterval         types.String  `tfsdk:"poll_interval"`
+	PollMaxInterval      types.String  `tfsdk:"poll_max_interval"`
+	UserAgentSuffix      types.String  `tfsdk:"user_agent_suffix"`
+
+	Endpoints *QwiltEndpointsModel `tfsdk:"endpoints"`
+}
+
+// QwiltEndpointsModel describes the base URL overrides of the Qwilt services.
+type QwiltEndpointsModel struct {
+	MediaSites  types.String `tfsdk:"media_sites"`
+	CertManager types.String `tfsdk:"cert_manager"`
+	DeviceIp    types.String `tfsdk:"device_ip"`
+	Login       types.String `tfsdk:"login"`
 }
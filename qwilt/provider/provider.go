@@ -10,16 +10,22 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn"
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/api"
 	cdnclient "github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/client"
 	"github.com/Qwilt/terraform-provider-qwilt/qwilt/cdn/model"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -96,7 +102,66 @@ func (p *qwiltCDNProvider) Configure(ctx context.Context, req provider.Configure
 	logMsg := fmt.Sprintf("Creating Qwilt CDN API client with env_type: %s, api_key length: %d", cfg.EnvType, len(cfg.XApiToken))
 	tflog.Debug(ctx, logMsg)
 
-	client, err := cdnclient.NewClient(cfg.EnvType, cfg.Username, cfg.Password, cfg.XApiToken)
+	transportCfg := cdnclient.TransportConfig{
+		ProxyUrl:           cfg.HttpProxy,
+		CaBundle:           cfg.CaBundle,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		ClientCertificate:  cfg.ClientCertificate,
+		ClientKey:          cfg.ClientKey,
+	}
+	if cfg.RequestTimeout != "" {
+		transportCfg.Timeout, _ = time.ParseDuration(cfg.RequestTimeout)
+	}
+	if cfg.InsecureSkipVerify {
+		tflog.Warn(ctx, "TLS certificate verification of the Qwilt CDN API is disabled")
+	}
+	httpClient, err := cdnclient.NewHTTPClient(transportCfg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Configure Qwilt CDN API Client Transport",
+			"An unexpected error occurred when configuring the HTTP transport of the Qwilt API client. "+
+				"Please check the http_proxy, ca_bundle, client_certificate and client_key settings.\n\n"+
+				"Qwilt API Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	retryMaxWait := cdnclient.DEFAULT_RETRY_MAX_WAIT
+	if cfg.RetryMaxWait != "" {
+		retryMaxWait, _ = time.ParseDuration(cfg.RetryMaxWait)
+	}
+	pollInterval, pollMaxInterval := cdnclient.DEFAULT_POLL_INTERVAL, cdnclient.DEFAULT_POLL_MAX_INTERVAL
+	if cfg.PollInterval != "" {
+		pollInterval, _ = time.ParseDuration(cfg.PollInterval)
+	}
+	if cfg.PollMaxInterval != "" {
+		pollMaxInterval, _ = time.ParseDuration(cfg.PollMaxInterval)
+	}
+	responseCacheTtl := cdnclient.DEFAULT_RESPONSE_CACHE_TTL
+	if cfg.ResponseCacheTtl != "" {
+		responseCacheTtl, _ = time.ParseDuration(cfg.ResponseCacheTtl)
+	}
+
+	opts := []cdnclient.Option{
+		cdnclient.WithEnvType(cfg.EnvType),
+		cdnclient.WithCredentials(cfg.Username, cfg.Password),
+		cdnclient.WithAPIKey(cfg.XApiToken),
+		cdnclient.WithHTTPClient(httpClient),
+		cdnclient.WithRetries(int(cfg.MaxRetries), retryMaxWait),
+		cdnclient.WithPollInterval(pollInterval, pollMaxInterval),
+		cdnclient.WithRateLimit(cfg.MaxRequestsPerSecond),
+		cdnclient.WithResponseCacheTTL(responseCacheTtl),
+		cdnclient.WithGzipRequests(cfg.GzipRequests),
+		cdnclient.WithUserAgent(cdnclient.UserAgent(p.version, req.TerraformVersion, cfg.UserAgentSuffix)),
+	}
+	for service, endpoint := range cfg.Endpoints {
+		if endpoint != "" {
+			tflog.Debug(ctx, "Overriding Qwilt CDN API endpoint", map[string]any{"service": service, "endpoint": endpoint})
+			opts = append(opts, cdnclient.WithEndpoint(service, endpoint))
+		}
+	}
+
+	client, err := cdnclient.New(opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Qwilt CDN API Client",
@@ -107,10 +172,11 @@ func (p *qwiltCDNProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	providerData := cdn.NewProviderData(client, cfg)
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 
-	tflog.Info(ctx, "Configured Qwilt CDN client", map[string]any{"success": true})
+	tflog.Info(ctx, "Configured Qwilt CDN client", map[string]any{"success": true, "correlation_id": client.CorrelationId()})
 }
 
 func (p *qwiltCDNProvider) parseConfig(config QwiltProviderModel) model.Settings {
@@ -121,6 +187,35 @@ func (p *qwiltCDNProvider) parseConfig(config QwiltProviderModel) model.Settings
 		Username:  os.Getenv("QCDN_USERNAME"),
 		Password:  os.Getenv("QCDN_PASSWORD"),
 		XApiToken: os.Getenv("QCDN_API_KEY"),
+
+		MaxRetries:   cdnclient.DEFAULT_MAX_RETRIES,
+		RetryMaxWait: os.Getenv("QCDN_RETRY_MAX_WAIT"),
+
+		HttpProxy:         os.Getenv("QCDN_HTTP_PROXY"),
+		CaBundle:          os.Getenv("QCDN_CA_BUNDLE"),
+		ClientCertificate: os.Getenv("QCDN_CLIENT_CERTIFICATE"),
+		ClientKey:         os.Getenv("QCDN_CLIENT_KEY"),
+		RequestTimeout:    os.Getenv("QCDN_REQUEST_TIMEOUT"),
+		ResponseCacheTtl:  os.Getenv("QCDN_RESPONSE_CACHE_TTL"),
+		PollInterval:      os.Getenv("QCDN_POLL_INTERVAL"),
+		PollMaxInterval:   os.Getenv("QCDN_POLL_MAX_INTERVAL"),
+		UserAgentSuffix:   os.Getenv("QCDN_USER_AGENT_SUFFIX"),
+
+		Endpoints: map[string]string{
+			api.SITES_HOSTNAME:        os.Getenv("QCDN_ENDPOINT_MEDIA_SITES"),
+			api.CERT_MANAGER_HOSTNAME: os.Getenv("QCDN_ENDPOINT_CERT_MANAGER"),
+			api.DEVICE_IP_HOSTNAME:    os.Getenv("QCDN_ENDPOINT_DEVICE_IP"),
+			api.LOGIN_HOSTNAME:        os.Getenv("QCDN_ENDPOINT_LOGIN"),
+		},
+	}
+
+	if maxRetries := os.Getenv("QCDN_MAX_RETRIES"); maxRetries != "" {
+		value, err := strconv.ParseInt(maxRetries, 10, 64)
+		if err != nil {
+			// Flagged as invalid by isConfigValid
+			value = -1
+		}
+		cfg.MaxRetries = value
 	}
 
 	if !config.EnvType.IsNull() {
@@ -139,6 +234,88 @@ func (p *qwiltCDNProvider) parseConfig(config QwiltProviderModel) model.Settings
 		cfg.XApiToken = config.XApiToken.ValueString()
 	}
 
+	if !config.MaxRetries.IsNull() {
+		cfg.MaxRetries = config.MaxRetries.ValueInt64()
+	}
+
+	if !config.RetryMaxWait.IsNull() {
+		cfg.RetryMaxWait = config.RetryMaxWait.ValueString()
+	}
+
+	cfg.InsecureSkipVerify, _ = strconv.ParseBool(os.Getenv("QCDN_INSECURE_SKIP_VERIFY"))
+	cfg.GzipRequests, _ = strconv.ParseBool(os.Getenv("QCDN_GZIP_REQUESTS"))
+
+	if maxRequestsPerSecond := os.Getenv("QCDN_MAX_REQUESTS_PER_SECOND"); maxRequestsPerSecond != "" {
+		value, err := strconv.ParseFloat(maxRequestsPerSecond, 64)
+		if err != nil {
+			// Flagged as invalid by isConfigValid
+			value = -1
+		}
+		cfg.MaxRequestsPerSecond = value
+	}
+
+	if !config.HttpProxy.IsNull() {
+		cfg.HttpProxy = config.HttpProxy.ValueString()
+	}
+
+	if !config.CaBundle.IsNull() {
+		cfg.CaBundle = config.CaBundle.ValueString()
+	}
+
+	if !config.InsecureSkipVerify.IsNull() {
+		cfg.InsecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	}
+
+	if !config.ClientCertificate.IsNull() {
+		cfg.ClientCertificate = config.ClientCertificate.ValueString()
+	}
+
+	if !config.ClientKey.IsNull() {
+		cfg.ClientKey = config.ClientKey.ValueString()
+	}
+
+	if !config.RequestTimeout.IsNull() {
+		cfg.RequestTimeout = config.RequestTimeout.ValueString()
+	}
+
+	if !config.MaxRequestsPerSecond.IsNull() {
+		cfg.MaxRequestsPerSecond = config.MaxRequestsPerSecond.ValueFloat64()
+	}
+
+	if !config.ResponseCacheTtl.IsNull() {
+		cfg.ResponseCacheTtl = config.ResponseCacheTtl.ValueString()
+	}
+
+	if !config.GzipRequests.IsNull() {
+		cfg.GzipRequests = config.GzipRequests.ValueBool()
+	}
+
+	if !config.PollInterval.IsNull() {
+		cfg.PollInterval = config.PollInterval.ValueString()
+	}
+
+	if !config.PollMaxInterval.IsNull() {
+		cfg.PollMaxInterval = config.PollMaxInterval.ValueString()
+	}
+
+	if !config.UserAgentSuffix.IsNull() {
+		cfg.UserAgentSuffix = config.UserAgentSuffix.ValueString()
+	}
+
+	if config.Endpoints != nil {
+		endpoints := map[string]types.String{
+			api.SITES_HOSTNAME:        config.Endpoints.MediaSites,
+			api.CERT_MANAGER_HOSTNAME: config.Endpoints.CertManager,
+			api.DEVICE_IP_HOSTNAME:    config.Endpoints.DeviceIp,
+			api.LOGIN_HOSTNAME:        config.Endpoints.Login,
+		}
+		for service, endpoint := range endpoints {
+			if !endpoint.IsNull() {
+				cfg.Endpoints[service] = endpoint.ValueString()
+			}
+		}
+	}
+
 	return cfg
 }
 
@@ -179,5 +356,120 @@ func (p *qwiltCDNProvider) isConfigValid(cfg model.Settings, resp *provider.Conf
 			}
 		}
 	}
+
+	if cfg.MaxRetries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid max_retries",
+			"The provider cannot create the Qwilt CDN Sites API client as max_retries must be a non-negative integer. "+
+				"Either set the value statically in the configuration, or use the QCDN_MAX_RETRIES environment variable.",
+		)
+	}
+	if cfg.RetryMaxWait != "" {
+		if wait, err := time.ParseDuration(cfg.RetryMaxWait); err != nil || wait < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_max_wait"),
+				"Invalid retry_max_wait",
+				"The provider cannot create the Qwilt CDN Sites API client as retry_max_wait is not a valid duration, such as \"30s\". "+
+					"Either set the value statically in the configuration, or use the QCDN_RETRY_MAX_WAIT environment variable.",
+			)
+		}
+	}
+	if cfg.RequestTimeout != "" {
+		if timeout, err := time.ParseDuration(cfg.RequestTimeout); err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid request_timeout",
+				"The provider cannot create the Qwilt CDN Sites API client as request_timeout is not a valid positive duration, such as \"40s\". "+
+					"Either set the value statically in the configuration, or use the QCDN_REQUEST_TIMEOUT environment variable.",
+			)
+		}
+	}
+	if cfg.MaxRequestsPerSecond < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_requests_per_second"),
+			"Invalid max_requests_per_second",
+			"The provider cannot create the Qwilt CDN Sites API client as max_requests_per_second must be a non-negative number. "+
+				"Either set the value statically in the configuration, or use the QCDN_MAX_REQUESTS_PER_SECOND environment variable.",
+		)
+	}
+	if cfg.ResponseCacheTtl != "" {
+		if ttl, err := time.ParseDuration(cfg.ResponseCacheTtl); err != nil || ttl < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("response_cache_ttl"),
+				"Invalid response_cache_ttl",
+				"The provider cannot create the Qwilt CDN Sites API client as response_cache_ttl is not a valid non-negative duration, such as \"30s\". "+
+					"Either set the value statically in the configuration, or use the QCDN_RESPONSE_CACHE_TTL environment variable.",
+			)
+		}
+	}
+	if cfg.PollInterval != "" {
+		if interval, err := time.ParseDuration(cfg.PollInterval); err != nil || interval <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("poll_interval"),
+				"Invalid poll_interval",
+				"The provider cannot create the Qwilt CDN Sites API client as poll_interval is not a valid positive duration, such as \"3s\". "+
+					"Either set the value statically in the configuration, or use the QCDN_POLL_INTERVAL environment variable.",
+			)
+		}
+	}
+	if cfg.PollMaxInterval != "" {
+		if interval, err := time.ParseDuration(cfg.PollMaxInterval); err != nil || interval <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("poll_max_interval"),
+				"Invalid poll_max_interval",
+				"The provider cannot create the Qwilt CDN Sites API client as poll_max_interval is not a valid positive duration, such as \"30s\". "+
+					"Either set the value statically in the configuration, or use the QCDN_POLL_MAX_INTERVAL environment variable.",
+			)
+		}
+	}
+	if cfg.PollInterval != "" || cfg.PollMaxInterval != "" {
+		interval, maxInterval := cdnclient.DEFAULT_POLL_INTERVAL, cdnclient.DEFAULT_POLL_MAX_INTERVAL
+		if cfg.PollInterval != "" {
+			interval, _ = time.ParseDuration(cfg.PollInterval)
+		}
+		if cfg.PollMaxInterval != "" {
+			maxInterval, _ = time.ParseDuration(cfg.PollMaxInterval)
+		}
+		if interval > 0 && maxInterval > 0 && interval > maxInterval {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("poll_max_interval"),
+				"Invalid poll_max_interval",
+				"The provider cannot create the Qwilt CDN Sites API client as poll_max_interval ("+maxInterval.String()+") is shorter than poll_interval ("+interval.String()+"). "+
+					"Either set the values statically in the configuration, or use the QCDN_POLL_INTERVAL and QCDN_POLL_MAX_INTERVAL environment variables.",
+			)
+		}
+	}
+	if strings.ContainsFunc(cfg.UserAgentSuffix, unicode.IsControl) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user_agent_suffix"),
+			"Invalid user_agent_suffix",
+			"The provider cannot create the Qwilt CDN Sites API client as user_agent_suffix contains control characters, such as a line break. "+
+				"Either set the value statically in the configuration, or use the QCDN_USER_AGENT_SUFFIX environment variable.",
+		)
+	}
+	if (cfg.ClientCertificate == "") != (cfg.ClientKey == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_certificate"),
+			"Incomplete mTLS configuration",
+			"The provider cannot create the Qwilt CDN Sites API client as client_certificate and client_key must be set together. "+
+				"Either set the values statically in the configuration, or use the QCDN_CLIENT_CERTIFICATE and QCDN_CLIENT_KEY environment variables.",
+		)
+	}
+
+	for service, endpoint := range cfg.Endpoints {
+		if endpoint == "" {
+			continue
+		}
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			attribute := strings.ReplaceAll(service, "-", "_")
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoints").AtName(attribute),
+				"Invalid "+attribute+" endpoint",
+				fmt.Sprintf("The provider cannot create the Qwilt CDN Sites API client as the %s endpoint %q is not an absolute http(s) URL. "+
+					"Either set the value statically in the configuration, or use the QCDN_ENDPOINT_%s environment variable.", attribute, endpoint, strings.ToUpper(attribute)),
+			)
+		}
+	}
 	return true
 }
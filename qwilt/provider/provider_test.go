@@ -0,0 +1,88 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// The resource.UnitTest cases run the provider in-process against the fake Qwilt APIs of package fakeapi,
+// so they need neither credentials nor network, only a terraform binary (found in the PATH or
+// through TF_ACC_TERRAFORM_PATH). The acceptance tests of package cdn run against a real environment.
+
+// testProviderFactories - Serves the provider from the test process
+func testProviderFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"qwilt": providerserver.NewProtocol6WithError(NewQwiltProvider("test")),
+	}
+}
+
+// newTestApi - Starts a fake of the Qwilt APIs for the test, and returns it with the provider configuration pointing at it
+func newTestApi(t *testing.T) (*fakeapi.Server, string) {
+	server := fakeapi.NewServer()
+	t.Cleanup(server.Close)
+
+	return server, fmt.Sprintf(`
+provider "qwilt" {
+	api_key       = "fake-api-key"
+	poll_interval = "10ms"
+
+	endpoints {
+		media_sites  = %[1]q
+		cert_manager = %[1]q
+		device_ip    = %[1]q
+		login        = %[1]q
+	}
+}
+`, server.URL)
+}
+
+func TestProviderUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+provider "qwilt" {
+	alias       = "invalid"
+	api_key     = "fake-api-key"
+	max_retries = -1
+}
+
+data "qwilt_cdn_origin_allow_list" "test" {
+	provider = qwilt.invalid
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid max_retries`),
+			},
+			{
+				Config: providerConfig + `
+provider "qwilt" {
+	alias         = "invalid"
+	api_key       = "fake-api-key"
+	poll_interval = "1m"
+}
+
+data "qwilt_cdn_origin_allow_list" "test" {
+	provider = qwilt.invalid
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid poll_max_interval`),
+			},
+		},
+	})
+}
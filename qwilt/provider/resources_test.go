@@ -0,0 +1,207 @@
+// Package cdn
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright (c) 2024 Qwilt Inc.
+package provider
+
+import (
+	"testing"
+
+	"github.com/Qwilt/terraform-provider-qwilt/qwilt/internal/fakeapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testSiteConfig = `
+resource "qwilt_cdn_site" "test" {
+	site_name = "unit-test site"
+}
+
+resource "qwilt_cdn_site_configuration" "test" {
+	site_id            = qwilt_cdn_site.test.site_id
+	change_description = "unit test"
+	host_index         = jsonencode({
+		hosts = [{
+			host = "www.unit-test.example.com"
+			"host-metadata" = {
+				metadata = []
+				paths    = []
+			}
+		}]
+	})
+}
+`
+
+func TestSiteResourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_site" "test" {
+	site_name = "unit-test site"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("qwilt_cdn_site.test", "site_id"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site.test", "site_name", "unit-test site"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site.test", "owner_org_id", fakeapi.ORG_ID),
+					resource.TestCheckResourceAttr("qwilt_cdn_site.test", "routing_method", "DNS"),
+				),
+			},
+			{
+				ResourceName:      "qwilt_cdn_site.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"force_overwrite",
+				},
+			},
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_site" "test" {
+	site_name = "unit-test site renamed"
+}
+`,
+				Check: resource.TestCheckResourceAttr("qwilt_cdn_site.test", "site_name", "unit-test site renamed"),
+			},
+		},
+	})
+}
+
+func TestSiteConfigurationResourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testSiteConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("qwilt_cdn_site_configuration.test", "site_id", "qwilt_cdn_site.test", "site_id"),
+					resource.TestCheckResourceAttrSet("qwilt_cdn_site_configuration.test", "revision_id"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_configuration.test", "revision_num", "1"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_configuration.test", "change_description", "unit test"),
+				),
+			},
+		},
+	})
+}
+
+func TestSiteActivationResourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testSiteConfig + `
+resource "qwilt_cdn_site_activation" "test" {
+	site_id             = qwilt_cdn_site_configuration.test.site_id
+	revision_id         = qwilt_cdn_site_configuration.test.revision_id
+	wait_for_completion = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("qwilt_cdn_site_activation.test", "revision_id", "qwilt_cdn_site_configuration.test", "revision_id"),
+					resource.TestCheckResourceAttrSet("qwilt_cdn_site_activation.test", "publish_id"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation.test", "target", "ga"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation.test", "operation_type", "Publish"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation.test", "publish_status", "Success"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation.test", "is_active", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestSiteActivationStagingResourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testSiteConfig + `
+resource "qwilt_cdn_site_activation_staging" "test" {
+	site_id     = qwilt_cdn_site_configuration.test.site_id
+	revision_id = qwilt_cdn_site_configuration.test.revision_id
+
+	timeouts {
+		create = "1m"
+	}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("qwilt_cdn_site_activation_staging.test", "publish_id"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation_staging.test", "target", "staging"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation_staging.test", "publish_acceptance_status", "Accepted"),
+					resource.TestCheckResourceAttr("qwilt_cdn_site_activation_staging.test", "timeouts.create", "1m"),
+				),
+			},
+		},
+	})
+}
+
+func TestCertificateResourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_certificate" "test" {
+	certificate       = base64encode("fake certificate")
+	certificate_chain = base64encode("fake certificate chain")
+	private_key       = base64encode("fake private key")
+	description       = "unit test"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("qwilt_cdn_certificate.test", "cert_id"),
+					resource.TestCheckResourceAttr("qwilt_cdn_certificate.test", "description", "unit test"),
+					resource.TestCheckResourceAttr("qwilt_cdn_certificate.test", "tenant", fakeapi.ORG_ID),
+				),
+			},
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_certificate" "test" {
+	certificate       = base64encode("fake certificate")
+	certificate_chain = base64encode("fake certificate chain")
+	private_key       = base64encode("fake private key")
+	description       = "unit test updated"
+}
+`,
+				Check: resource.TestCheckResourceAttr("qwilt_cdn_certificate.test", "description", "unit test updated"),
+			},
+		},
+	})
+}
+
+func TestCertificateTemplateResourceUnit(t *testing.T) {
+	_, providerConfig := newTestApi(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "qwilt_cdn_certificate_template" "test" {
+	common_name       = "www.unit-test.example.com"
+	sans              = ["cdn.unit-test.example.com"]
+	organization_name = "Unit Test"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("qwilt_cdn_certificate_template.test", "certificate_template_id"),
+					resource.TestCheckResourceAttr("qwilt_cdn_certificate_template.test", "csr_ids.#", "1"),
+				),
+			},
+		},
+	})
+}
@@ -58,6 +58,106 @@ func AddResponseSchema(resp *provider.SchemaResponse) {
 				Optional:            true,
 				Sensitive:           true,
 			},
+			// HTTP
+			"max_retries": schema.Int64Attribute{
+				Description:         "Maximum number of times a request is retried after a transient API failure. Defaults to 3. May also be provided via QCDN_MAX_RETRIES environment variable.",
+				MarkdownDescription: "Maximum number of times a request is retried after a transient API failure (HTTP 429, 502, 503, 504 or a network error). Only idempotent requests are retried, except for rate-limit responses. Defaults to 3, set to 0 to disable retries. May also be set by the QCDN_MAX_RETRIES environment variable.",
+				Optional:            true,
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Description:         "Maximum time to wait between retries, as a duration string such as \"30s\". Defaults to 30s. May also be provided via QCDN_RETRY_MAX_WAIT environment variable.",
+				MarkdownDescription: "Maximum time to wait between retries, as a duration string such as `30s`. A `Retry-After` header returned by the API is honored up to this limit. Defaults to `30s`. May also be set by the QCDN_RETRY_MAX_WAIT environment variable.",
+				Optional:            true,
+			},
+			"http_proxy": schema.StringAttribute{
+				Description:         "URL of the proxy used for all API requests. When not set, the standard HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables apply. May also be provided via QCDN_HTTP_PROXY environment variable.",
+				MarkdownDescription: "URL of the proxy used for all API requests, such as `http://proxy.example.com:3128`. When not set, the standard `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables apply. May also be set by the QCDN_HTTP_PROXY environment variable.",
+				Optional:            true,
+			},
+			"ca_bundle": schema.StringAttribute{
+				Description:         "PEM encoded CA certificates, or the path of a PEM file, trusted in addition to the system roots. May also be provided via QCDN_CA_BUNDLE environment variable.",
+				MarkdownDescription: "PEM encoded CA certificates, or the path of a PEM file, trusted in addition to the system roots. Use it when the API is reached through a TLS-intercepting proxy. May also be set by the QCDN_CA_BUNDLE environment variable.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description:         "Skip verification of the API server certificates. For lab use only. May also be provided via QCDN_INSECURE_SKIP_VERIFY environment variable.",
+				MarkdownDescription: "Skip verification of the API server certificates. **For lab use only.** May also be set by the QCDN_INSECURE_SKIP_VERIFY environment variable.",
+				Optional:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				Description:         "PEM encoded client certificate, or the path of a PEM file, presented for mTLS. Requires client_key. May also be provided via QCDN_CLIENT_CERTIFICATE environment variable.",
+				MarkdownDescription: "PEM encoded client certificate, or the path of a PEM file, presented for mTLS. Requires `client_key`. May also be set by the QCDN_CLIENT_CERTIFICATE environment variable.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				Description:         "PEM encoded private key of the client certificate, or the path of a PEM file. May also be provided via QCDN_CLIENT_KEY environment variable.",
+				MarkdownDescription: "PEM encoded private key of the client certificate, or the path of a PEM file. Requires `client_certificate`. May also be set by the QCDN_CLIENT_KEY environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"request_timeout": schema.StringAttribute{
+				Description:         "Timeout of a single API request, as a duration string such as \"40s\". Defaults to 40s. May also be provided via QCDN_REQUEST_TIMEOUT environment variable.",
+				MarkdownDescription: "Timeout of a single API request, as a duration string such as `40s`. Defaults to `40s`. May also be set by the QCDN_REQUEST_TIMEOUT environment variable.",
+				Optional:            true,
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Description:         "Maximum rate of API requests sent by the provider, shared by all resources and data sources. 0 or unset means unlimited. May also be provided via QCDN_MAX_REQUESTS_PER_SECOND environment variable.",
+				MarkdownDescription: "Maximum rate of API requests sent by the provider, shared by all resources and data sources. Use it to stay below the API rate limits when running with a high `-parallelism`. 0 or unset means unlimited. May also be set by the QCDN_MAX_REQUESTS_PER_SECOND environment variable.",
+				Optional:            true,
+			},
+			"response_cache_ttl": schema.StringAttribute{
+				Description:         "How long the provider reuses the details of a site, certificate or certificate template it has already read, as a duration string such as \"30s\". Defaults to 30s, set to 0s to disable. May also be provided via QCDN_RESPONSE_CACHE_TTL environment variable.",
+				MarkdownDescription: "How long the provider reuses the details of a site, certificate or certificate template it has already read, as a duration string such as `30s`. Identical concurrent lookups share a single request, and any change made by the provider to an object drops its cached details. The active revision checked before a republish is always read from the API. Defaults to `30s`, set to `0s` to disable. May also be set by the QCDN_RESPONSE_CACHE_TTL environment variable.",
+				Optional:            true,
+			},
+			"gzip_requests": schema.BoolAttribute{
+				Description:         "Send the large request bodies, such as site configurations with a large host index, gzip-encoded. Enable it only when the Qwilt APIs you use accept gzip-encoded bodies. Defaults to false. May also be provided via QCDN_GZIP_REQUESTS environment variable.",
+				MarkdownDescription: "Send the large request bodies, such as site configurations with a large host index, gzip-encoded. Enable it only when the Qwilt APIs you use accept gzip-encoded bodies; a body the API rejects is sent again plain. Defaults to `false`. May also be set by the QCDN_GZIP_REQUESTS environment variable.",
+				Optional:            true,
+			},
+			"poll_interval": schema.StringAttribute{
+				Description:         "How long the provider waits before checking again on a long running operation, such as the validation of a publishing operation, as a duration string such as \"3s\". The wait doubles on each check, up to poll_max_interval. Defaults to 3s. May also be provided via QCDN_POLL_INTERVAL environment variable.",
+				MarkdownDescription: "How long the provider waits before checking again on a long running operation, such as the validation of a publishing operation, as a duration string such as `3s`. The wait doubles on each check, up to `poll_max_interval`. Defaults to `3s`. May also be set by the QCDN_POLL_INTERVAL environment variable.",
+				Optional:            true,
+			},
+			"poll_max_interval": schema.StringAttribute{
+				Description:         "The longest wait between two checks on a long running operation, as a duration string such as \"30s\". Must not be shorter than poll_interval. Defaults to 30s. May also be provided via QCDN_POLL_MAX_INTERVAL environment variable.",
+				MarkdownDescription: "The longest wait between two checks on a long running operation, as a duration string such as `30s`. Must not be shorter than `poll_interval`. Defaults to `30s`. May also be set by the QCDN_POLL_MAX_INTERVAL environment variable.",
+				Optional:            true,
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Description:         "Text appended to the User-Agent of the API requests, such as the name of the pipeline running Terraform. May also be provided via QCDN_USER_AGENT_SUFFIX environment variable.",
+				MarkdownDescription: "Text appended to the User-Agent of the API requests, such as the name of the pipeline running Terraform. The User-Agent is `terraform-provider-qwilt/<version> terraform/<version>` followed by this text. May also be set by the QCDN_USER_AGENT_SUFFIX environment variable.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"endpoints": schema.SingleNestedBlock{
+				Description:         "Overrides the base URL of the Qwilt APIs, for example to use an API gateway or a local stand-in. Each URL may also be provided via QCDN_ENDPOINT_<SERVICE> environment variables.",
+				MarkdownDescription: "Overrides the base URL of the Qwilt APIs, for example to use an API gateway or a local stand-in. When set, the value replaces the URL derived from `env_type` for that API only.",
+				Attributes: map[string]schema.Attribute{
+					"media_sites": schema.StringAttribute{
+						Description:         "Base URL of the Sites API. May also be provided via QCDN_ENDPOINT_MEDIA_SITES environment variable.",
+						MarkdownDescription: "Base URL of the Sites API, such as `https://media-sites.cqloud.com`. May also be set by the QCDN_ENDPOINT_MEDIA_SITES environment variable.",
+						Optional:            true,
+					},
+					"cert_manager": schema.StringAttribute{
+						Description:         "Base URL of the Certificate Manager API. May also be provided via QCDN_ENDPOINT_CERT_MANAGER environment variable.",
+						MarkdownDescription: "Base URL of the Certificate Manager API, such as `https://cert-manager.cqloud.com`. May also be set by the QCDN_ENDPOINT_CERT_MANAGER environment variable.",
+						Optional:            true,
+					},
+					"device_ip": schema.StringAttribute{
+						Description:         "Base URL of the Origin Allow List API. May also be provided via QCDN_ENDPOINT_DEVICE_IP environment variable.",
+						MarkdownDescription: "Base URL of the Origin Allow List API, such as `https://device-ip.cqloud.com`. May also be set by the QCDN_ENDPOINT_DEVICE_IP environment variable.",
+						Optional:            true,
+					},
+					"login": schema.StringAttribute{
+						Description:         "Base URL of the login API used for username/password authentication. May also be provided via QCDN_ENDPOINT_LOGIN environment variable.",
+						MarkdownDescription: "Base URL of the login API used for username/password authentication, such as `https://login.cqloud.com`. May also be set by the QCDN_ENDPOINT_LOGIN environment variable.",
+						Optional:            true,
+					},
+				},
+			},
 		},
 	}
 }